@@ -43,6 +43,12 @@ const (
 	appName          = "GaiaApp"
 	Bech32MainPrefix = "cosmos"
 	Bip44CoinType    = 494
+
+	// upgradeZoracleStoreIndexes is the name of the upgrade that sets the zoracle parameters added
+	// since genesis to their defaults, moves the zoracle pending list to indexed store keys and
+	// indexes the raw data requests, results, search keys, assignments and expirations of existing
+	// requests.
+	upgradeZoracleStoreIndexes = "zoracle-store-indexes"
 )
 
 var (
@@ -77,13 +83,15 @@ var (
 
 	// module account permissions
 	maccPerms = map[string][]string{
-		auth.FeeCollectorName:           nil,
-		distr.ModuleName:                nil,
-		mint.ModuleName:                 {supply.Minter},
-		staking.BondedPoolName:          {supply.Burner, supply.Staking},
-		staking.NotBondedPoolName:       {supply.Burner, supply.Staking},
-		gov.ModuleName:                  {supply.Burner},
-		transfer.GetModuleAccountName(): {supply.Minter, supply.Burner},
+		auth.FeeCollectorName:            nil,
+		distr.ModuleName:                 nil,
+		mint.ModuleName:                  {supply.Minter},
+		staking.BondedPoolName:           {supply.Burner, supply.Staking},
+		staking.NotBondedPoolName:        {supply.Burner, supply.Staking},
+		gov.ModuleName:                   {supply.Burner},
+		transfer.GetModuleAccountName():  {supply.Minter, supply.Burner},
+		zoracle.ModuleName:               nil,
+		zoracle.ReferenceDataAccountName: nil,
 	}
 )
 
@@ -193,7 +201,7 @@ func NewGaiaApp(
 		appCodec, keys[auth.StoreKey], app.subspaces[auth.ModuleName], auth.ProtoBaseAccount,
 	)
 	app.bankKeeper = bank.NewBaseKeeper(
-		appCodec, keys[bank.StoreKey], app.accountKeeper, app.subspaces[bank.ModuleName], app.ModuleAccountAddrs(),
+		appCodec, keys[bank.StoreKey], app.accountKeeper, app.subspaces[bank.ModuleName], app.BlockedAddrs(),
 	)
 	app.supplyKeeper = supply.NewKeeper(
 		appCodec, keys[supply.StoreKey], app.accountKeeper, app.bankKeeper, maccPerms,
@@ -207,10 +215,10 @@ func NewGaiaApp(
 	)
 	app.distrKeeper = distr.NewKeeper(
 		appCodec, keys[distr.StoreKey], app.subspaces[distr.ModuleName], app.bankKeeper, &stakingKeeper,
-		app.supplyKeeper, auth.FeeCollectorName, app.ModuleAccountAddrs(),
+		app.supplyKeeper, auth.FeeCollectorName, app.BlockedAddrs(),
 	)
 	app.mintKeeper = mint.NewKeeper(appCodec, keys[mint.StoreKey], app.subspaces[mint.ModuleName], &stakingKeeper, app.supplyKeeper, auth.FeeCollectorName)
-	app.distrKeeper = distr.NewKeeper(appCodec, keys[distr.StoreKey], app.subspaces[distr.ModuleName], app.bankKeeper, &stakingKeeper, app.supplyKeeper, auth.FeeCollectorName, app.ModuleAccountAddrs())
+	app.distrKeeper = distr.NewKeeper(appCodec, keys[distr.StoreKey], app.subspaces[distr.ModuleName], app.bankKeeper, &stakingKeeper, app.supplyKeeper, auth.FeeCollectorName, app.BlockedAddrs())
 	app.slashingKeeper = slashing.NewKeeper(
 		appCodec, keys[slashing.StoreKey], &stakingKeeper, app.subspaces[slashing.ModuleName],
 	)
@@ -259,10 +267,20 @@ func NewGaiaApp(
 		cdc,
 		keys[zoracle.StoreKey],
 		app.bankKeeper,
+		app.supplyKeeper,
 		app.stakingKeeper,
 		app.ibcKeeper.ChannelKeeper,
 		app.subspaces[zoracle.ModuleName],
+		auth.FeeCollectorName,
 	)
+	app.upgradeKeeper.SetUpgradeHandler(upgradeZoracleStoreIndexes, func(ctx sdk.Context, plan upgrade.Plan) {
+		app.zoracleKeeper.MigrateParams(ctx)
+		app.zoracleKeeper.MigratePendingResolveList(ctx)
+		app.zoracleKeeper.MigrateRawDataRequestCounts(ctx)
+		app.zoracleKeeper.MigrateLatestResults(ctx)
+		app.zoracleKeeper.MigrateRequestIndexes(ctx)
+		app.zoracleKeeper.MigrateAssignments(ctx)
+	})
 
 	// NOTE: Any module instantiated in the module manager that is later modified
 	// must be passed by reference here.
@@ -287,7 +305,9 @@ func NewGaiaApp(
 	// there is nothing left over in the validator fee pool, so as to keep the
 	// CanWithdrawInvariant invariant.
 
-	app.mm.SetOrderBeginBlockers(upgrade.ModuleName, mint.ModuleName, distr.ModuleName, slashing.ModuleName, staking.ModuleName)
+	app.mm.SetOrderBeginBlockers(
+		upgrade.ModuleName, mint.ModuleName, distr.ModuleName, slashing.ModuleName, staking.ModuleName, zoracle.ModuleName,
+	)
 	app.mm.SetOrderEndBlockers(crisis.ModuleName, gov.ModuleName, zoracle.ModuleName, staking.ModuleName)
 
 	// NOTE: The genutils module must occur after staking so that pools are
@@ -381,6 +401,15 @@ func (app *GaiaApp) ModuleAccountAddrs() map[string]bool {
 	return modAccAddrs
 }
 
+// BlockedAddrs returns the module account addresses that may not receive funds. The reference
+// data account is left out so that it can be funded to pay for reference data requests.
+func (app *GaiaApp) BlockedAddrs() map[string]bool {
+	blockedAddrs := app.ModuleAccountAddrs()
+	delete(blockedAddrs, supply.NewModuleAddress(zoracle.ReferenceDataAccountName).String())
+
+	return blockedAddrs
+}
+
 // Codec returns the application's sealed codec.
 func (app *GaiaApp) Codec() *codec.Codec {
 	return app.cdc
@@ -10,6 +10,7 @@ import (
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/cosmos/cosmos-sdk/simapp"
+	"github.com/cosmos/gaia/x/zoracle"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 )
@@ -32,10 +33,21 @@ func TestBlackListedAddrs(t *testing.T) {
 	gapp := NewGaiaApp(log.NewTMLogger(log.NewSyncWriter(os.Stdout)), db, nil, true, 0, map[int64]bool{}, "")
 
 	for acc := range maccPerms {
-		require.True(t, gapp.bankKeeper.BlacklistedAddr(gapp.supplyKeeper.GetModuleAddress(acc)))
+		// The reference data account must be fundable to pay for reference data requests.
+		blacklisted := acc != zoracle.ReferenceDataAccountName
+		require.Equal(t, blacklisted, gapp.bankKeeper.BlacklistedAddr(gapp.supplyKeeper.GetModuleAddress(acc)), acc)
 	}
 }
 
+// ensure that every module with block hooks runs them
+func TestZoracleBlockers(t *testing.T) {
+	db := db.NewMemDB()
+	gapp := NewGaiaApp(log.NewTMLogger(log.NewSyncWriter(os.Stdout)), db, nil, true, 0, map[int64]bool{}, "")
+
+	require.Contains(t, gapp.mm.OrderBeginBlockers, zoracle.ModuleName)
+	require.Contains(t, gapp.mm.OrderEndBlockers, zoracle.ModuleName)
+}
+
 func setGenesis(gapp *GaiaApp) error {
 	genesisState := simapp.NewDefaultGenesisState()
 	stateBytes, err := codec.MarshalJSONIndent(gapp.Codec(), genesisState)
@@ -23,9 +23,19 @@ import (
 	dbm "github.com/tendermint/tm-db"
 
 	"github.com/cosmos/gaia/app"
+	"github.com/cosmos/gaia/owasm"
+	"github.com/cosmos/gaia/x/zoracle"
 )
 
-const flagInvCheckPeriod = "inv-check-period"
+const (
+	flagInvCheckPeriod = "inv-check-period"
+	flagOwasmCacheSize = "owasm-cache-size"
+
+	// The Owasm settings are read from the [owasm] section of app.toml, and the flags above
+	// override them. The VM engine is not a node setting: gas usage depends on the engine, so
+	// every node runs the default one.
+	configOwasmCacheSize = "owasm.cache_size"
+)
 
 var invCheckPeriod uint
 
@@ -59,6 +69,7 @@ func main() {
 	rootCmd.AddCommand(flags.NewCompletionCmd(rootCmd, true))
 	rootCmd.AddCommand(testnetCmd(ctx, cdc, app.ModuleBasics, bank.GenesisBalancesIterator{}))
 	rootCmd.AddCommand(replayCmd())
+	rootCmd.AddCommand(owasmCmd())
 	rootCmd.AddCommand(debug.Cmd(cdc))
 
 	server.AddCommands(ctx, cdc, rootCmd, newApp, exportAppStateAndTMValidators)
@@ -67,6 +78,11 @@ func main() {
 	executor := cli.PrepareBaseCmd(rootCmd, "GA", app.DefaultNodeHome)
 	rootCmd.PersistentFlags().UintVar(&invCheckPeriod, flagInvCheckPeriod,
 		0, "Assert registered invariants every N blocks")
+	rootCmd.PersistentFlags().Int(flagOwasmCacheSize, owasm.DefaultCacheSize,
+		"Number of compiled oracle scripts to keep in memory (cache_size in the [owasm] section of app.toml)")
+	if err := viper.BindPFlag(configOwasmCacheSize, rootCmd.PersistentFlags().Lookup(flagOwasmCacheSize)); err != nil {
+		panic(err)
+	}
 	err := executor.Execute()
 	if err != nil {
 		panic(err)
@@ -85,6 +101,11 @@ func newApp(logger log.Logger, db dbm.DB, traceStore io.Writer) abci.Application
 		skipUpgradeHeights[int64(h)] = true
 	}
 
+	owasm.SetCacheSize(viper.GetInt(configOwasmCacheSize))
+	if viper.GetBool("instrumentation.prometheus") {
+		zoracle.SetMetrics(zoracle.PrometheusMetrics(viper.GetString("instrumentation.namespace")))
+	}
+
 	return app.NewGaiaApp(
 		logger, db, traceStore, true, invCheckPeriod, skipUpgradeHeights,
 		viper.GetString(flags.FlagHome),
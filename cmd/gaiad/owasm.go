@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/gaia/owasm"
+	"github.com/cosmos/gaia/x/zoracle"
+)
+
+const (
+	flagEntry      = "entry"
+	flagCalldata   = "calldata"
+	flagReports    = "reports"
+	flagValidators = "validators"
+	flagGas        = "gas"
+	flagParams     = "params"
+
+	flagMaxMemoryPages     = "max-memory-pages"
+	flagMaxTableSize       = "max-table-size"
+	flagMaxValueSlots      = "max-value-slots"
+	flagMaxCallStackDepth  = "max-call-stack-depth"
+	flagDefaultMemoryPages = "default-memory-pages"
+	flagDefaultTableSize   = "default-table-size"
+)
+
+func owasmCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "owasm",
+		Short: "Tools for developing Owasm oracle scripts",
+	}
+	cmd.AddCommand(owasmRunCmd())
+	return cmd
+}
+
+func owasmRunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run <wasm>",
+		Short: "Run an oracle script locally without deploying it",
+		Long: `Run an oracle script locally without deploying it. The prepare phase prints the
+external data the script requests. The execute phase reads the raw data reports from the
+JSON file given by --reports, which holds a list of {"external_id", "validator", "exit_code", "data"}
+objects, where validator is the index of the reporting validator. Both phases are run
+unless --entry is given. The script runs under the default zoracle parameters, or under the
+parameters in the JSON file given by --params, such as the output of the zoracle params query.
+The VM limit flags override the limits of the parameters.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			code, err := ioutil.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			entry, err := cmd.Flags().GetString(flagEntry)
+			if err != nil {
+				return err
+			}
+			calldata, err := cmd.Flags().GetBytesHex(flagCalldata)
+			if err != nil {
+				return err
+			}
+			validatorCount, err := cmd.Flags().GetInt64(flagValidators)
+			if err != nil {
+				return err
+			}
+			gasLimit, err := cmd.Flags().GetUint64(flagGas)
+			if err != nil {
+				return err
+			}
+
+			var reports []owasm.LocalReport
+			reportsPath, err := cmd.Flags().GetString(flagReports)
+			if err != nil {
+				return err
+			}
+			if reportsPath != "" {
+				reports, err = owasm.ReadLocalReports(reportsPath)
+				if err != nil {
+					return err
+				}
+			}
+			if validatorCount == 0 {
+				validatorCount = 1
+				for _, report := range reports {
+					if report.Validator >= validatorCount {
+						validatorCount = report.Validator + 1
+					}
+				}
+			}
+
+			var entries []string
+			switch entry {
+			case "":
+				entries = []string{"prepare", "execute"}
+			case "prepare", "execute":
+				entries = []string{entry}
+			default:
+				return fmt.Errorf("invalid entry %s, must be prepare or execute", entry)
+			}
+
+			params, err := readOwasmParams(cmd)
+			if err != nil {
+				return err
+			}
+			now := time.Now().Unix()
+			env := &owasm.LocalEnvironment{
+				RequestID:                       1,
+				ValidatorAddresses:              localValidatorAddresses(validatorCount),
+				SufficientValidatorCount:        validatorCount,
+				PrepareBlockTime:                now,
+				AggregateBlockTime:              now,
+				MaximumResultSize:               params.MaxResultSize,
+				MaximumCalldataOfDataSourceSize: params.MaxCalldataSize,
+				Reports:                         reports,
+			}
+
+			out := cmd.OutOrStdout()
+			for _, entry := range entries {
+				env.SetAggregating(entry == "execute")
+				tracer := &owasm.Tracer{}
+				result, _, err := owasm.ExecuteWithTracer(
+					env, code, entry, calldata, gasLimit, params.VMLimits(), params.GasSchedule, tracer,
+				)
+				printOwasmPhase(out, env, entry, result, tracer)
+				if err != nil {
+					return fmt.Errorf("%s failed: %s", entry, err)
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String(flagEntry, "", "Entry to run, prepare or execute (default both)")
+	cmd.Flags().BytesHex(flagCalldata, nil, "Calldata used in calling the oracle script")
+	cmd.Flags().String(flagReports, "", "JSON file of raw data reports used by the execute phase")
+	cmd.Flags().Int64(flagValidators, 0, "Number of requested validators (default derived from reports)")
+	cmd.Flags().Uint64(flagGas, zoracle.DefaultParams().EndBlockExecuteGasLimit, "Gas limit of each phase")
+	cmd.Flags().String(flagParams, "", "JSON file of the zoracle parameters to run under (default the genesis defaults)")
+	for _, limit := range owasmLimitFlags(nil) {
+		cmd.Flags().Int64(limit.name, 0, limit.usage)
+	}
+	return cmd
+}
+
+// owasmLimitFlag binds a VM limit flag to the parameter that it overrides.
+type owasmLimitFlag struct {
+	name  string
+	usage string
+	param *int64
+}
+
+func owasmLimitFlags(params *zoracle.Params) []owasmLimitFlag {
+	if params == nil {
+		params = &zoracle.Params{}
+	}
+	return []owasmLimitFlag{
+		{flagMaxMemoryPages, "Maximum number of memory pages the script can grow to", &params.MaxMemoryPages},
+		{flagMaxTableSize, "Maximum number of table entries of the script", &params.MaxTableSize},
+		{flagMaxValueSlots, "Maximum number of value slots of a function call", &params.MaxValueSlots},
+		{flagMaxCallStackDepth, "Maximum call stack depth of the script", &params.MaxCallStackDepth},
+		{flagDefaultMemoryPages, "Memory pages of a script that does not declare its memory", &params.DefaultMemoryPages},
+		{flagDefaultTableSize, "Table size of a script that does not declare its table", &params.DefaultTableSize},
+	}
+}
+
+// readOwasmParams returns the zoracle parameters given by --params, or the default parameters,
+// with the VM limits overridden by the limit flags that are set. Parameters missing from the
+// file keep their default values.
+func readOwasmParams(cmd *cobra.Command) (zoracle.Params, error) {
+	params := zoracle.DefaultParams()
+	paramsPath, err := cmd.Flags().GetString(flagParams)
+	if err != nil {
+		return params, err
+	}
+	if paramsPath != "" {
+		bz, err := ioutil.ReadFile(paramsPath)
+		if err != nil {
+			return params, err
+		}
+		// Decoding replaces the whole struct, so lay the file over the encoded defaults first.
+		fields := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(zoracle.ModuleCdc.MustMarshalJSON(params), &fields); err != nil {
+			return params, err
+		}
+		if err := json.Unmarshal(bz, &fields); err != nil {
+			return params, fmt.Errorf("invalid params file %s: %s", paramsPath, err)
+		}
+		merged, err := json.Marshal(fields)
+		if err != nil {
+			return params, err
+		}
+		if err := zoracle.ModuleCdc.UnmarshalJSON(merged, &params); err != nil {
+			return params, fmt.Errorf("invalid params file %s: %s", paramsPath, err)
+		}
+	}
+	for _, limit := range owasmLimitFlags(&params) {
+		if !cmd.Flags().Changed(limit.name) {
+			continue
+		}
+		value, err := cmd.Flags().GetInt64(limit.name)
+		if err != nil {
+			return params, err
+		}
+		*limit.param = value
+	}
+	return params, nil
+}
+
+// localValidatorAddresses returns distinct placeholder validator addresses for local runs.
+func localValidatorAddresses(count int64) [][]byte {
+	addresses := make([][]byte, count)
+	for i := range addresses {
+		address := make([]byte, 20)
+		address[19] = byte(i + 1)
+		addresses[i] = address
+	}
+	return addresses
+}
+
+func printOwasmPhase(out io.Writer, env *owasm.LocalEnvironment, entry string, result []byte, tracer *owasm.Tracer) {
+	fmt.Fprintf(out, "== %s ==\n", entry)
+	if entry == "prepare" {
+		fmt.Fprintln(out, "requested external data:")
+		for _, request := range env.Requests {
+			fmt.Fprintf(out, "  external ID %d: data source %d, calldata %s\n",
+				request.ExternalID, request.DataSourceID, hex.EncodeToString(request.Calldata))
+		}
+	}
+	if result != nil {
+		fmt.Fprintf(out, "result: %s\n", hex.EncodeToString(result))
+	}
+	fmt.Fprintln(out, tracer)
+}
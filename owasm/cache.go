@@ -0,0 +1,130 @@
+package owasm
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+)
+
+// DefaultCacheSize is the default number of compiled Owasm modules kept in memory.
+const DefaultCacheSize = 100
+
+// Cache is an LRU cache of compiled Owasm modules keyed by the SHA-256 hash of their code.
+// It is safe for concurrent use, so CheckTx and DeliverTx can share the same instance.
+type Cache struct {
+	mu     sync.Mutex
+	size   int
+	ll     *list.List
+	items  map[cacheKey]*list.Element
+	hits   uint64
+	misses uint64
+}
+
+// cacheKey identifies a compiled module by the engine that compiled it, the resource
+// limits and gas schedule it was compiled with, and its code hash.
+type cacheKey struct {
+	engine       string
+	limits       Limits
+	scheduleHash [sha256.Size]byte
+	codeHash     [sha256.Size]byte
+}
+
+type cacheEntry struct {
+	key    cacheKey
+	module Module
+}
+
+// NewCache creates a new Cache that holds at most size compiled modules. A size of zero
+// disables caching, in which case every lookup compiles the code again.
+func NewCache(size int) *Cache {
+	return &Cache{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[cacheKey]*list.Element),
+	}
+}
+
+// getOrCompile returns the compiled module with the given key, compiling it with the given
+// function and remembering the result if it is not already in the cache.
+func (c *Cache) getOrCompile(key cacheKey, compile func() (Module, error)) (Module, error) {
+	c.mu.Lock()
+	if elem, ok := c.items[key]; ok {
+		c.ll.MoveToFront(elem)
+		c.hits++
+		c.mu.Unlock()
+		return elem.Value.(*cacheEntry).module, nil
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	// Compile outside of the lock so that a slow compilation does not block other lookups.
+	module, err := compile()
+	if err != nil {
+		return nil, err
+	}
+	c.add(key, module)
+	return module, nil
+}
+
+// add inserts the compiled module to the cache, evicting the least recently used entry
+// if the cache is full.
+func (c *Cache) add(key cacheKey, module Module) {
+	if c.size <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.items[key]; ok {
+		c.ll.MoveToFront(elem)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&cacheEntry{key: key, module: module})
+	if c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// Len returns the number of compiled modules currently in the cache.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// Hits returns the number of lookups that found the compiled module in the cache.
+func (c *Cache) Hits() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits
+}
+
+// Misses returns the number of lookups that had to compile the code.
+func (c *Cache) Misses() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.misses
+}
+
+var (
+	// defaultCacheMu guards defaultCache, which may be replaced while scripts are running.
+	defaultCacheMu sync.RWMutex
+	// defaultCache is the process-wide cache used by Execute.
+	defaultCache = NewCache(DefaultCacheSize)
+)
+
+// SetCacheSize replaces the process-wide compiled module cache with an empty one that holds
+// at most size modules. Executions that already started keep using the previous cache.
+func SetCacheSize(size int) {
+	defaultCacheMu.Lock()
+	defer defaultCacheMu.Unlock()
+	defaultCache = NewCache(size)
+}
+
+// GetCache returns the process-wide compiled module cache used by Execute.
+func GetCache() *Cache {
+	defaultCacheMu.RLock()
+	defer defaultCacheMu.RUnlock()
+	return defaultCache
+}
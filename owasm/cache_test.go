@@ -0,0 +1,132 @@
+package owasm
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"testing"
+)
+
+// countingCompile returns a compile function that counts how many times it was called.
+func countingCompile(count *int) func() (Module, error) {
+	return func() (Module, error) {
+		*count++
+		return &lifeModule{}, nil
+	}
+}
+
+func testCacheKey(code string) cacheKey {
+	return cacheKey{
+		engine:       DefaultEngineName,
+		limits:       DefaultLimits,
+		scheduleHash: DefaultGasSchedule().Hash(),
+		codeHash:     sha256.Sum256([]byte(code)),
+	}
+}
+
+func TestCacheHit(t *testing.T) {
+	cache := NewCache(2)
+	compiled := 0
+	for i := 0; i < 3; i++ {
+		if _, err := cache.getOrCompile(testCacheKey("a"), countingCompile(&compiled)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if compiled != 1 {
+		t.Errorf("compiled %d times, expected 1", compiled)
+	}
+	if cache.Hits() != 2 || cache.Misses() != 1 {
+		t.Errorf("hits %d and misses %d, expected 2 and 1", cache.Hits(), cache.Misses())
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewCache(2)
+	compiled := 0
+	for _, code := range []string{"a", "b", "a", "c"} {
+		if _, err := cache.getOrCompile(testCacheKey(code), countingCompile(&compiled)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if cache.Len() != 2 {
+		t.Fatalf("cache holds %d modules, expected 2", cache.Len())
+	}
+	// "b" was the least recently used module when "c" got added, so only "b" is compiled again.
+	compiled = 0
+	for _, code := range []string{"a", "c"} {
+		if _, err := cache.getOrCompile(testCacheKey(code), countingCompile(&compiled)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if compiled != 0 {
+		t.Errorf("compiled %d times, expected a and c to be cached", compiled)
+	}
+	if _, err := cache.getOrCompile(testCacheKey("b"), countingCompile(&compiled)); err != nil {
+		t.Fatal(err)
+	}
+	if compiled != 1 {
+		t.Errorf("compiled %d times, expected b to be evicted", compiled)
+	}
+}
+
+func TestCacheSizeZeroDisablesCaching(t *testing.T) {
+	cache := NewCache(0)
+	compiled := 0
+	for i := 0; i < 2; i++ {
+		if _, err := cache.getOrCompile(testCacheKey("a"), countingCompile(&compiled)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if compiled != 2 || cache.Len() != 0 {
+		t.Errorf("compiled %d times with %d cached modules, expected 2 and 0", compiled, cache.Len())
+	}
+}
+
+func TestCacheKeyedByEngineLimitsAndSchedule(t *testing.T) {
+	otherEngine := testCacheKey("a")
+	otherEngine.engine = "other"
+	otherLimits := testCacheKey("a")
+	otherLimits.limits.MaxCallStackDepth++
+	schedule := DefaultGasSchedule()
+	schedule.UnknownCost++
+	otherSchedule := testCacheKey("a")
+	otherSchedule.scheduleHash = schedule.Hash()
+
+	cache := NewCache(10)
+	compiled := 0
+	for _, key := range []cacheKey{testCacheKey("a"), otherEngine, otherLimits, otherSchedule, testCacheKey("a")} {
+		if _, err := cache.getOrCompile(key, countingCompile(&compiled)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if compiled != 4 {
+		t.Errorf("compiled %d times, expected each distinct key to be compiled once", compiled)
+	}
+}
+
+func TestExecuteUsesCache(t *testing.T) {
+	code, err := ioutil.ReadFile("res/silly.wasm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetCacheSize(10)
+	defer SetCacheSize(DefaultCacheSize)
+
+	limits := DefaultLimits
+	schedule := DefaultGasSchedule()
+	execute := func() {
+		Execute(newConformanceEnvironment(), code, "execute", []byte{}, conformanceGasLimit, limits, schedule)
+	}
+	execute()
+	execute()
+	if GetCache().Misses() != 1 || GetCache().Hits() != 1 {
+		t.Errorf("misses %d and hits %d, expected 1 and 1", GetCache().Misses(), GetCache().Hits())
+	}
+	// A governance change of the limits or the gas schedule must recompile the script.
+	limits.MaxCallStackDepth++
+	execute()
+	schedule.UnknownCost++
+	execute()
+	if GetCache().Misses() != 3 {
+		t.Errorf("misses %d, expected 3", GetCache().Misses())
+	}
+}
@@ -0,0 +1,165 @@
+package owasm
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrOutOfGas is returned by Instance.Call when the script runs out of gas.
+var ErrOutOfGas = errors.New("out of gas")
+
+// DefaultEngineName is the name of the virtual machine backend that nodes run scripts with.
+const DefaultEngineName = "life"
+
+// Engine is a WebAssembly virtual machine backend capable of running Owasm scripts. All
+// engines must produce identical results and gas usage for the same script and inputs.
+type Engine interface {
+	// Name returns the unique name used to select the engine with SetEngine.
+	Name() string
+
+	// Compile validates and compiles the given Wasm code into a module that can be
+	// instantiated many times. Gas is metered per instruction using the given policy, and
+	// every instance is bounded by the given resource limits.
+	Compile(code []byte, policy GasPolicy, limits Limits) (Module, error)
+}
+
+// Limits bounds the resources that a single Owasm script instance may use.
+type Limits struct {
+	MaxMemoryPages     int64
+	MaxTableSize       int64
+	MaxValueSlots      int64
+	MaxCallStackDepth  int64
+	DefaultMemoryPages int64
+	DefaultTableSize   int64
+}
+
+// Upper bounds of the resource limits, so that no configuration can let a single script
+// instance exhaust the memory of the node.
+const (
+	MaxMemoryPagesBound    = 16384 // 1 GiB of 64 KiB pages
+	MaxTableSizeBound      = 1 << 20
+	MaxValueSlotsBound     = 1 << 24
+	MaxCallStackDepthBound = 1 << 14
+)
+
+// Validate checks that every limit is positive and within its upper bound, and that the
+// default memory size does not exceed the maximum memory size.
+func (l Limits) Validate() error {
+	bounds := []struct {
+		name  string
+		value int64
+		max   int64
+	}{
+		{"max memory pages", l.MaxMemoryPages, MaxMemoryPagesBound},
+		{"max table size", l.MaxTableSize, MaxTableSizeBound},
+		{"max value slots", l.MaxValueSlots, MaxValueSlotsBound},
+		{"max call stack depth", l.MaxCallStackDepth, MaxCallStackDepthBound},
+		{"default memory pages", l.DefaultMemoryPages, MaxMemoryPagesBound},
+		{"default table size", l.DefaultTableSize, MaxTableSizeBound},
+	}
+	for _, b := range bounds {
+		if b.value <= 0 || b.value > b.max {
+			return fmt.Errorf("Validate: %s must be between 1 and %d (%d)", b.name, b.max, b.value)
+		}
+	}
+	if l.DefaultMemoryPages > l.MaxMemoryPages {
+		return fmt.Errorf(
+			"Validate: default memory pages (%d) must not exceed max memory pages (%d)",
+			l.DefaultMemoryPages, l.MaxMemoryPages,
+		)
+	}
+	return nil
+}
+
+// DefaultLimits are the resource limits used when the chain parameters are not available.
+var DefaultLimits = Limits{
+	MaxMemoryPages:     1024,
+	MaxTableSize:       1024,
+	MaxValueSlots:      65536,
+	MaxCallStackDepth:  128,
+	DefaultMemoryPages: 64,
+	DefaultTableSize:   65536,
+}
+
+// Module is a compiled Owasm script. Implementations must be safe for concurrent use.
+type Module interface {
+	// Instantiate creates a fresh instance of the module with its own memory, whose imports
+	// are resolved by the given resolver and whose gas usage is capped at gasLimit.
+	Instantiate(resolver HostResolver, gasLimit uint64) (Instance, error)
+}
+
+// Instance is a single run of a compiled Owasm script.
+type Instance interface {
+	// Call runs the exported function with the given name until it returns or traps. It
+	// returns ErrOutOfGas if the trap is caused by exceeding the gas limit.
+	Call(entry string) error
+
+	// GasUsed returns the amount of gas consumed by the instance so far.
+	GasUsed() uint64
+
+	// ConsumeGas charges the given amount of gas to the instance. It panics, trapping the
+	// running script, if the gas limit is exceeded.
+	ConsumeGas(amount uint64)
+
+	// Memory returns the linear memory of the instance.
+	Memory() []byte
+}
+
+// HostFunction is a function that an Owasm script can import from the host. It receives
+// the calling instance and the call arguments, and may panic to trap the script.
+type HostFunction func(inst Instance, args []int64) int64
+
+// HostResolver resolves the host functions imported by an Owasm script.
+type HostResolver interface {
+	ResolveFunc(module, field string) HostFunction
+}
+
+var engines = make(map[string]Engine)
+
+// defaultEngine is the engine used by Execute.
+var defaultEngine Engine
+
+// RegisterEngine makes the given engine available for selection. It panics if another
+// engine with the same name is already registered.
+func RegisterEngine(engine Engine) {
+	if _, ok := engines[engine.Name()]; ok {
+		panic(fmt.Errorf("RegisterEngine: duplicate engine name: %s", engine.Name()))
+	}
+	engines[engine.Name()] = engine
+}
+
+// GetEngine returns the registered engine with the given name.
+func GetEngine(name string) (Engine, bool) {
+	engine, ok := engines[name]
+	return engine, ok
+}
+
+// EngineNames returns the names of all registered engines in sorted order.
+func EngineNames() []string {
+	names := make([]string, 0, len(engines))
+	for name := range engines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetEngine selects the registered engine used by Execute. It must be called before any
+// Owasm script gets executed. Gas usage and results are part of consensus, so a node must not
+// select an engine other than DefaultEngineName unless every node of its network does the same.
+func SetEngine(name string) error {
+	engine, ok := GetEngine(name)
+	if !ok {
+		return fmt.Errorf("SetEngine: unknown owasm engine: %s", name)
+	}
+	defaultEngine = engine
+	return nil
+}
+
+func init() {
+	RegisterEngine(&lifeEngine{})
+	if err := SetEngine(DefaultEngineName); err != nil {
+		panic(err)
+	}
+}
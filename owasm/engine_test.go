@@ -0,0 +1,171 @@
+package owasm
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+const conformanceGasLimit = 10000000
+
+func newConformanceEnvironment() *mockExecutionEnvironment {
+	return &mockExecutionEnvironment{
+		requestID:                         1,
+		requestedValidatorCount:           2,
+		sufficientValidatorCount:          2,
+		receivedValidatorCount:            2,
+		prepareBlockTime:                  1588888888,
+		aggregateBlockTime:                1588888890,
+		validatorAddresses:                [][]byte{[]byte("validator1"), []byte("validator2")},
+		externalDataResults:               [][][]byte{{[]byte("1"), []byte("2")}, {[]byte("3"), []byte("4")}},
+		maximumResultSize:                 1024,
+		maximumCalldataOfDataSourceSize:   1024,
+		requestExternalDataResultsCounter: [][]int64{{0, 0}, {0, 0}},
+		latestResults:                     map[string][]byte{"3:0000000000000000": []byte("12345")},
+	}
+}
+
+// conformanceOutcome is how a conformance run ended.
+type conformanceOutcome int
+
+const (
+	returned conformanceOutcome = iota
+	outOfGas
+	trapped
+)
+
+type conformanceOutput struct {
+	result        []byte
+	gasUsed       uint64
+	outcome       conformanceOutcome
+	externalCalls int
+}
+
+// conformanceCase is a fixture entry run with the given gas limit and its golden output,
+// recorded with the life engine and the default limits and gas schedule.
+type conformanceCase struct {
+	fixture  string
+	entry    string
+	gasLimit uint64
+	expected conformanceOutput
+}
+
+var conformanceCases = []conformanceCase{
+	{"allocate.wasm", "prepare", conformanceGasLimit, conformanceOutput{nil, 1, returned, 0}},
+	{"allocate.wasm", "execute", conformanceGasLimit, conformanceOutput{nil, 11858, returned, 0}},
+	{"allocate.wasm", "execute", 1000, conformanceOutput{nil, 969, outOfGas, 0}},
+	{"crypto_price.wasm", "prepare", conformanceGasLimit, conformanceOutput{nil, 905, trapped, 0}},
+	{"crypto_price.wasm", "execute", conformanceGasLimit, conformanceOutput{make([]byte, 8), 8964, returned, 0}},
+	{"crypto_price.wasm", "execute", 1000, conformanceOutput{nil, 961, outOfGas, 0}},
+	{"get_env.wasm", "prepare", conformanceGasLimit, conformanceOutput{nil, 1, returned, 0}},
+	{"get_env.wasm", "execute", conformanceGasLimit, conformanceOutput{make([]byte, 8), 1074, returned, 0}},
+	{"get_env.wasm", "execute", 1000, conformanceOutput{nil, 967, outOfGas, 0}},
+	{"main.wasm", "prepare", conformanceGasLimit, conformanceOutput{nil, 1, returned, 0}},
+	{"main.wasm", "execute", conformanceGasLimit, conformanceOutput{[]byte{3, 0, 0, 0, 0, 0, 0, 0}, 2793, returned, 0}},
+	{"main.wasm", "execute", 1000, conformanceOutput{nil, 992, outOfGas, 0}},
+	{"moresilly.wasm", "prepare", conformanceGasLimit, conformanceOutput{nil, 5086, trapped, 0}},
+	{"moresilly.wasm", "prepare", 1000, conformanceOutput{nil, 994, outOfGas, 0}},
+	{"moresilly.wasm", "execute", conformanceGasLimit, conformanceOutput{nil, 5085, trapped, 0}},
+	{"silly.wasm", "prepare", conformanceGasLimit, conformanceOutput{nil, 4333, returned, 2}},
+	{"silly.wasm", "prepare", 1000, conformanceOutput{nil, 46, outOfGas, 0}},
+	{"silly.wasm", "execute", conformanceGasLimit, conformanceOutput{[]byte("3"), 3731, returned, 0}},
+	{"silly.wasm", "execute", 1000, conformanceOutput{nil, 990, outOfGas, 0}},
+}
+
+func runOnEngine(t *testing.T, engine Engine, code []byte, entry string, gasLimit uint64) conformanceOutput {
+	module, err := engine.Compile(code, DefaultGasSchedule().Policy(), DefaultLimits)
+	if err != nil {
+		t.Fatalf("%s: failed to compile: %v", engine.Name(), err)
+	}
+	env := newConformanceEnvironment()
+	result, gasUsed, err := run(module, env, entry, []byte{}, gasLimit, DefaultGasSchedule(), nil)
+	output := conformanceOutput{result: result, gasUsed: gasUsed, externalCalls: len(env.requestedExternalData)}
+	switch {
+	case err == ErrOutOfGas:
+		output.outcome = outOfGas
+	case err != nil:
+		output.outcome = trapped
+	}
+	return output
+}
+
+// TestEngineConformance runs the Owasm fixtures on all registered engines and checks their
+// results, gas usage, external data requests, and failures against golden outputs.
+func TestEngineConformance(t *testing.T) {
+	if len(EngineNames()) == 0 {
+		t.Fatal("no owasm engines registered")
+	}
+	for _, c := range conformanceCases {
+		code, err := ioutil.ReadFile(filepath.Join("res", c.fixture))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range EngineNames() {
+			engine, _ := GetEngine(name)
+			got := runOnEngine(t, engine, code, c.entry, c.gasLimit)
+			where := fmt.Sprintf("%s %s with gas limit %d on %s", c.fixture, c.entry, c.gasLimit, name)
+			if !bytes.Equal(got.result, c.expected.result) {
+				t.Errorf("%s: result %x, expected %x", where, got.result, c.expected.result)
+			}
+			if got.gasUsed != c.expected.gasUsed {
+				t.Errorf("%s: gas used %d, expected %d", where, got.gasUsed, c.expected.gasUsed)
+			}
+			if got.outcome != c.expected.outcome {
+				t.Errorf("%s: outcome %d, expected %d", where, got.outcome, c.expected.outcome)
+			}
+			if got.externalCalls != c.expected.externalCalls {
+				t.Errorf("%s: %d external data requests, expected %d", where, got.externalCalls, c.expected.externalCalls)
+			}
+		}
+	}
+}
+
+// TestEngineConformanceCoversFixtures checks that every fixture has golden outputs.
+func TestEngineConformanceCoversFixtures(t *testing.T) {
+	fixtures, err := filepath.Glob("res/*.wasm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	covered := make(map[string]bool)
+	for _, c := range conformanceCases {
+		covered[c.fixture] = true
+	}
+	for _, fixture := range fixtures {
+		if !covered[filepath.Base(fixture)] {
+			t.Errorf("%s has no golden conformance outputs", fixture)
+		}
+	}
+}
+
+func TestLimitsValidate(t *testing.T) {
+	if err := DefaultLimits.Validate(); err != nil {
+		t.Fatalf("default limits are invalid: %v", err)
+	}
+	unlimited := DefaultLimits
+	unlimited.MaxMemoryPages = 0
+	negative := DefaultLimits
+	negative.MaxCallStackDepth = -1
+	tooLarge := DefaultLimits
+	tooLarge.MaxValueSlots = MaxValueSlotsBound + 1
+	defaultAboveMax := DefaultLimits
+	defaultAboveMax.DefaultMemoryPages = defaultAboveMax.MaxMemoryPages + 1
+	for _, limits := range []Limits{unlimited, negative, tooLarge, defaultAboveMax} {
+		if limits.Validate() == nil {
+			t.Errorf("expected %+v to be invalid", limits)
+		}
+	}
+
+	code, err := ioutil.ReadFile("res/silly.wasm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = Execute(
+		newConformanceEnvironment(), code, "execute", []byte{}, conformanceGasLimit,
+		defaultAboveMax, DefaultGasSchedule(),
+	)
+	if err == nil {
+		t.Error("expected Execute to reject invalid limits")
+	}
+}
@@ -1,9 +1,7 @@
 package owasm
 
 import (
-	"fmt"
-
-	"github.com/perlin-network/life/exec"
+	"crypto/sha256"
 )
 
 // Execute runs an Owasm script code by via the script's entryID. Note that
@@ -15,27 +13,74 @@ func Execute(
 	entry string,
 	calldata []byte,
 	gasLimit uint64,
+	limits Limits,
+	schedule GasSchedule,
 ) (result []byte, gasUsed uint64, err error) {
-	resolver := NewResolver(env, calldata)
-	vm, err := exec.NewVirtualMachine(code, exec.VMConfig{
-		EnableJIT:                false,
-		MaxMemoryPages:           1024,
-		MaxTableSize:             1024,
-		MaxValueSlots:            65536,
-		MaxCallStackDepth:        128,
-		DefaultMemoryPages:       64,
-		DefaultTableSize:         65536,
-		GasLimit:                 uint64(gasLimit),
-		DisableFloatingPoint:     false,
-		ReturnOnGasLimitExceeded: false,
-	}, resolver, &BandChainGasPolicy{})
+	return ExecuteWithTracer(env, code, entry, calldata, gasLimit, limits, schedule, nil)
+}
+
+// ExecuteWithTracer is like Execute, but also records the host function calls, debug logs,
+// and trap reason of the execution to the given tracer if it is not nil.
+func ExecuteWithTracer(
+	env ExecutionEnvironment,
+	code []byte,
+	entry string,
+	calldata []byte,
+	gasLimit uint64,
+	limits Limits,
+	schedule GasSchedule,
+	tracer *Tracer,
+) (result []byte, gasUsed uint64, err error) {
+	// Limits come from governance one parameter at a time, so a combination of them can still
+	// be invalid.
+	if err := limits.Validate(); err != nil {
+		if tracer != nil {
+			tracer.TrapReason = err.Error()
+		}
+		return nil, 0, err
+	}
+	engine := defaultEngine
+	key := cacheKey{
+		engine:       engine.Name(),
+		limits:       limits,
+		scheduleHash: schedule.Hash(),
+		codeHash:     sha256.Sum256(code),
+	}
+	module, err := GetCache().getOrCompile(key, func() (Module, error) {
+		return engine.Compile(code, schedule.Policy(), limits)
+	})
 	if err != nil {
+		if tracer != nil {
+			tracer.TrapReason = err.Error()
+		}
 		return nil, 0, err
 	}
-	entryID, ok := vm.GetFunctionExport(entry)
-	if !ok {
-		return nil, 0, fmt.Errorf("Execute: invalid owasm entry: %s", entry)
+	result, gasUsed, err = run(module, env, entry, calldata, gasLimit, schedule, tracer)
+	if tracer != nil {
+		tracer.GasUsed = gasUsed
+		if err != nil {
+			tracer.TrapReason = err.Error()
+		}
+	}
+	return result, gasUsed, err
+}
+
+// run instantiates the compiled module and calls the given entry with a fresh resolver that
+// charges host functions according to the given gas schedule.
+func run(
+	module Module,
+	env ExecutionEnvironment,
+	entry string,
+	calldata []byte,
+	gasLimit uint64,
+	schedule GasSchedule,
+	tracer *Tracer,
+) (result []byte, gasUsed uint64, err error) {
+	resolver := NewResolver(env, calldata, schedule, tracer)
+	inst, err := module.Instantiate(resolver, gasLimit)
+	if err != nil {
+		return nil, 0, err
 	}
-	_, err = vm.Run(int(entryID))
-	return resolver.result, vm.Gas, err
+	err = inst.Call(entry)
+	return resolver.result, inst.GasUsed(), err
 }
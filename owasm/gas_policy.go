@@ -1,9 +1,13 @@
 package owasm
 
 import (
-	"github.com/perlin-network/life/compiler"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sort"
 )
 
+// UnknownGasCost is the default cost of an instruction that is not listed in the gas schedule.
 const UnknownGasCost = 10000000
 
 var defaultGas = map[string]int64{
@@ -197,12 +201,187 @@ var defaultGas = map[string]int64{
 	"select": 3,
 }
 
-type BandChainGasPolicy struct{}
+// defaultHostCosts are the gas costs of the host functions that an Owasm script can import.
+// Functions that copy data across the VM boundary are also charged for each byte copied.
+var defaultHostCosts = map[string]HostCost{
+	"getCurrentRequestID":         {BaseCost: 100},
+	"getRequestedValidatorCount":  {BaseCost: 100},
+	"getSufficientValidatorCount": {BaseCost: 100},
+	"getReceivedValidatorCount":   {BaseCost: 100},
+	"getPrepareBlockTime":         {BaseCost: 100},
+	"getAggregateBlockTime":       {BaseCost: 100},
+	"readValidatorAddress":        {BaseCost: 200, CostPerByte: 3},
+	"getCallDataSize":             {BaseCost: 100},
+	"readCallData":                {BaseCost: 100, CostPerByte: 3},
+	"saveReturnData":              {BaseCost: 500, CostPerByte: 10},
+	"requestExternalData":         {BaseCost: 2000, CostPerByte: 10},
+	"getExternalDataStatusCode":   {BaseCost: 500, CostPerByte: 3},
+	"getExternalDataSize":         {BaseCost: 500, CostPerByte: 3},
+	"readExternalData":            {BaseCost: 500, CostPerByte: 3},
+	"getLatestResultSize":         {BaseCost: 500, CostPerByte: 3},
+	"readLatestResult":            {BaseCost: 500, CostPerByte: 3},
+	"log":                         {BaseCost: 100, CostPerByte: 3}, // Only useful when traced
+}
+
+// GasPolicy determines the gas cost of executing each Wasm instruction.
+type GasPolicy interface {
+	GetCost(op string) int64
+}
+
+// OpCost is the gas cost of a single Wasm instruction.
+type OpCost struct {
+	Op   string `json:"op" yaml:"op"`
+	Cost int64  `json:"cost" yaml:"cost"`
+}
+
+// HostCost is the gas cost of calling a single host function. CostPerByte is charged for
+// each byte that the call copies across the VM boundary or reads from the host state.
+type HostCost struct {
+	Function    string `json:"function" yaml:"function"`
+	BaseCost    uint64 `json:"base_cost" yaml:"base_cost"`
+	CostPerByte uint64 `json:"cost_per_byte" yaml:"cost_per_byte"`
+}
+
+// GasSchedule is a table of Wasm instruction and host function gas costs. Costs are kept as
+// lists sorted by op and function name so that the schedule has a single canonical encoding,
+// which identifies the schedule by its hash. A valid schedule lists every host function, so the
+// schedule alone determines what a script is charged.
+type GasSchedule struct {
+	UnknownCost int64      `json:"unknown_cost" yaml:"unknown_cost"`
+	Costs       []OpCost   `json:"costs" yaml:"costs"`
+	HostCosts   []HostCost `json:"host_costs" yaml:"host_costs"`
+}
+
+// defaultOpCosts returns the instruction costs of defaultGas sorted by op name.
+func defaultOpCosts() []OpCost {
+	costs := make([]OpCost, 0, len(defaultGas))
+	for op, cost := range defaultGas {
+		costs = append(costs, OpCost{Op: op, Cost: cost})
+	}
+	sort.Slice(costs, func(i, j int) bool { return costs[i].Op < costs[j].Op })
+	return costs
+}
+
+// DefaultGasSchedule returns the default gas schedule, which charges host functions according
+// to defaultHostCosts.
+func DefaultGasSchedule() GasSchedule {
+	hostCosts := make([]HostCost, 0, len(defaultHostCosts))
+	for function, cost := range defaultHostCosts {
+		cost.Function = function
+		hostCosts = append(hostCosts, cost)
+	}
+	sort.Slice(hostCosts, func(i, j int) bool { return hostCosts[i].Function < hostCosts[j].Function })
+	return GasSchedule{
+		UnknownCost: UnknownGasCost,
+		Costs:       defaultOpCosts(),
+		HostCosts:   hostCosts,
+	}
+}
+
+// LegacyGasSchedule returns the gas schedule that BandChain launched with, under which host
+// functions were free. Requests made before gas schedules were recorded are executed with it.
+func LegacyGasSchedule() GasSchedule {
+	hostCosts := make([]HostCost, 0, len(defaultHostCosts))
+	for function := range defaultHostCosts {
+		hostCosts = append(hostCosts, HostCost{Function: function})
+	}
+	sort.Slice(hostCosts, func(i, j int) bool { return hostCosts[i].Function < hostCosts[j].Function })
+	return GasSchedule{
+		UnknownCost: UnknownGasCost,
+		Costs:       defaultOpCosts(),
+		HostCosts:   hostCosts,
+	}
+}
+
+// Validate checks that the gas schedule is well-formed.
+func (s GasSchedule) Validate() error {
+	if s.UnknownCost <= 0 {
+		return fmt.Errorf("Validate: unknown op cost must be positive (%d)", s.UnknownCost)
+	}
+	for i, c := range s.Costs {
+		if c.Op == "" {
+			return fmt.Errorf("Validate: empty op name at index %d", i)
+		}
+		if c.Cost < 0 {
+			return fmt.Errorf("Validate: cost of %s must not be negative (%d)", c.Op, c.Cost)
+		}
+		if i > 0 && s.Costs[i-1].Op >= c.Op {
+			return fmt.Errorf("Validate: ops must be sorted and unique (%s, %s)", s.Costs[i-1].Op, c.Op)
+		}
+	}
+	for i, c := range s.HostCosts {
+		if _, ok := defaultHostCosts[c.Function]; !ok {
+			return fmt.Errorf("Validate: unknown host function: %s", c.Function)
+		}
+		if i > 0 && s.HostCosts[i-1].Function >= c.Function {
+			return fmt.Errorf(
+				"Validate: host functions must be sorted and unique (%s, %s)",
+				s.HostCosts[i-1].Function, c.Function,
+			)
+		}
+	}
+	if len(s.HostCosts) != len(defaultHostCosts) {
+		return fmt.Errorf(
+			"Validate: host costs must list all %d host functions (%d)", len(defaultHostCosts), len(s.HostCosts),
+		)
+	}
+	return nil
+}
+
+// Hash returns the SHA-256 hash of the canonical encoding of the gas schedule.
+func (s GasSchedule) Hash() [sha256.Size]byte {
+	h := sha256.New()
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(s.UnknownCost))
+	h.Write(buf)
+	for _, c := range s.Costs {
+		binary.BigEndian.PutUint64(buf, uint64(len(c.Op)))
+		h.Write(buf)
+		h.Write([]byte(c.Op))
+		binary.BigEndian.PutUint64(buf, uint64(c.Cost))
+		h.Write(buf)
+	}
+	for _, c := range s.HostCosts {
+		binary.BigEndian.PutUint64(buf, uint64(len(c.Function)))
+		h.Write(buf)
+		h.Write([]byte(c.Function))
+		binary.BigEndian.PutUint64(buf, c.BaseCost)
+		h.Write(buf)
+		binary.BigEndian.PutUint64(buf, c.CostPerByte)
+		h.Write(buf)
+	}
+	var hash [sha256.Size]byte
+	copy(hash[:], h.Sum(nil))
+	return hash
+}
+
+// Policy returns the GasPolicy that charges instructions according to the schedule.
+func (s GasSchedule) Policy() GasPolicy {
+	costs := make(map[string]int64, len(s.Costs))
+	for _, c := range s.Costs {
+		costs[c.Op] = c.Cost
+	}
+	return &schedulePolicy{costs: costs, unknownCost: s.UnknownCost}
+}
+
+// hostCostTable returns the host function costs of the schedule keyed by function name.
+func (s GasSchedule) hostCostTable() map[string]HostCost {
+	costs := make(map[string]HostCost, len(s.HostCosts))
+	for _, c := range s.HostCosts {
+		costs[c.Function] = c
+	}
+	return costs
+}
+
+type schedulePolicy struct {
+	costs       map[string]int64
+	unknownCost int64
+}
 
-func (p *BandChainGasPolicy) GetCost(key compiler.Instr) int64 {
-	gasCost, found := defaultGas[key.Op]
+func (p *schedulePolicy) GetCost(op string) int64 {
+	gasCost, found := p.costs[op]
 	if !found {
-		return UnknownGasCost
+		return p.unknownCost
 	}
 	return gasCost
 }
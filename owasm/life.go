@@ -0,0 +1,116 @@
+package owasm
+
+import (
+	"fmt"
+
+	"github.com/perlin-network/life/compiler"
+	"github.com/perlin-network/life/exec"
+)
+
+// lifeVMConfig returns the configuration used to compile Owasm scripts with the life engine.
+// The gas limit is set separately on each virtual machine instance.
+func lifeVMConfig(limits Limits) exec.VMConfig {
+	return exec.VMConfig{
+		EnableJIT:                false,
+		MaxMemoryPages:           int(limits.MaxMemoryPages),
+		MaxTableSize:             int(limits.MaxTableSize),
+		MaxValueSlots:            int(limits.MaxValueSlots),
+		MaxCallStackDepth:        int(limits.MaxCallStackDepth),
+		DefaultMemoryPages:       int(limits.DefaultMemoryPages),
+		DefaultTableSize:         int(limits.DefaultTableSize),
+		DisableFloatingPoint:     false,
+		ReturnOnGasLimitExceeded: false,
+	}
+}
+
+// lifeEngine runs Owasm scripts with the perlin-network/life interpreter.
+type lifeEngine struct{}
+
+func (lifeEngine) Name() string {
+	return "life"
+}
+
+func (lifeEngine) Compile(code []byte, policy GasPolicy, limits Limits) (_ Module, err error) {
+	// Limits come from governance, so a bad combination must not crash the node.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Compile: %v", r)
+		}
+	}()
+	module, err := exec.NewModule(code, lifeVMConfig(limits), &lifeImportResolver{}, &lifeGasPolicy{policy})
+	if err != nil {
+		return nil, err
+	}
+	return &lifeModule{module}, nil
+}
+
+// lifeGasPolicy adapts a GasPolicy to the life compiler.
+type lifeGasPolicy struct {
+	policy GasPolicy
+}
+
+func (p *lifeGasPolicy) GetCost(key compiler.Instr) int64 {
+	return p.policy.GetCost(key.Op)
+}
+
+type lifeModule struct {
+	module *exec.Module
+}
+
+func (m *lifeModule) Instantiate(resolver HostResolver, gasLimit uint64) (_ Instance, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Instantiate: %v", r)
+		}
+	}()
+	inst := &lifeInstance{vm: m.module.NewVirtualMachine()}
+	inst.vm.ImportResolver = &lifeImportResolver{host: resolver, inst: inst}
+	inst.vm.Config.GasLimit = gasLimit
+	return inst, nil
+}
+
+type lifeInstance struct {
+	vm *exec.VirtualMachine
+}
+
+func (inst *lifeInstance) Call(entry string) error {
+	entryID, ok := inst.vm.GetFunctionExport(entry)
+	if !ok {
+		return fmt.Errorf("Call: invalid owasm entry: %s", entry)
+	}
+	_, err := inst.vm.Run(entryID)
+	// life traps with this message whenever AddAndCheckGas exceeds the gas limit.
+	if err != nil && err.Error() == "gas limit exceeded" {
+		return ErrOutOfGas
+	}
+	return err
+}
+
+func (inst *lifeInstance) GasUsed() uint64 {
+	return inst.vm.Gas
+}
+
+func (inst *lifeInstance) ConsumeGas(amount uint64) {
+	inst.vm.AddAndCheckGas(amount)
+}
+
+func (inst *lifeInstance) Memory() []byte {
+	return inst.vm.Memory
+}
+
+// lifeImportResolver binds host functions to a life virtual machine.
+type lifeImportResolver struct {
+	host HostResolver
+	inst *lifeInstance
+}
+
+func (r *lifeImportResolver) ResolveFunc(module, field string) exec.FunctionImport {
+	f := r.host.ResolveFunc(module, field)
+	return func(vm *exec.VirtualMachine) int64 {
+		return f(r.inst, vm.GetCurrentFrame().Locals)
+	}
+}
+
+func (r *lifeImportResolver) ResolveGlobal(module, field string) int64 {
+	panic(fmt.Errorf("ResolveGlobal is not supported by owasm!"))
+}
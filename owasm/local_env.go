@@ -0,0 +1,151 @@
+package owasm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// LocalReport is a raw data report that a validator submits for an external data ID.
+type LocalReport struct {
+	ExternalID int64  `json:"external_id"`
+	Validator  int64  `json:"validator"`
+	ExitCode   uint8  `json:"exit_code"`
+	Data       string `json:"data"`
+}
+
+// LocalRequest is a raw data request that an Owasm script makes during preparation.
+type LocalRequest struct {
+	DataSourceID int64
+	ExternalID   int64
+	Calldata     []byte
+}
+
+// LocalEnvironment is an ExecutionEnvironment that runs Owasm scripts outside of the chain,
+// with validators and raw data reports supplied by the script developer.
+type LocalEnvironment struct {
+	RequestID                       int64
+	ValidatorAddresses              [][]byte
+	SufficientValidatorCount        int64
+	PrepareBlockTime                int64
+	AggregateBlockTime              int64
+	MaximumResultSize               int64
+	MaximumCalldataOfDataSourceSize int64
+	Reports                         []LocalReport
+
+	// Requests collects the raw data requests made by the script.
+	Requests []LocalRequest
+
+	// aggregating is true while the execute phase is running.
+	aggregating bool
+}
+
+// ReadLocalReports reads a JSON list of raw data reports from the given file.
+func ReadLocalReports(path string) ([]LocalReport, error) {
+	bz, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var reports []LocalReport
+	if err := json.Unmarshal(bz, &reports); err != nil {
+		return nil, fmt.Errorf("ReadLocalReports: invalid reports file %s: %v", path, err)
+	}
+	return reports, nil
+}
+
+// SetAggregating switches the environment between the preparation and aggregation phases.
+func (env *LocalEnvironment) SetAggregating(aggregating bool) {
+	env.aggregating = aggregating
+}
+
+func (env *LocalEnvironment) GetCurrentRequestID() int64 {
+	return env.RequestID
+}
+
+func (env *LocalEnvironment) GetRequestedValidatorCount() int64 {
+	return int64(len(env.ValidatorAddresses))
+}
+
+func (env *LocalEnvironment) GetSufficientValidatorCount() int64 {
+	return env.SufficientValidatorCount
+}
+
+func (env *LocalEnvironment) GetReceivedValidatorCount() int64 {
+	if !env.aggregating {
+		return 0
+	}
+	received := make(map[int64]bool)
+	for _, report := range env.Reports {
+		received[report.Validator] = true
+	}
+	return int64(len(received))
+}
+
+func (env *LocalEnvironment) GetPrepareBlockTime() int64 {
+	return env.PrepareBlockTime
+}
+
+func (env *LocalEnvironment) GetAggregateBlockTime() int64 {
+	if !env.aggregating {
+		return 0
+	}
+	return env.AggregateBlockTime
+}
+
+func (env *LocalEnvironment) GetValidatorAddress(validatorIndex int64) ([]byte, error) {
+	if validatorIndex < 0 || validatorIndex >= int64(len(env.ValidatorAddresses)) {
+		return nil, fmt.Errorf("GetValidatorAddress: validator index out of range (%d)", validatorIndex)
+	}
+	return env.ValidatorAddresses[validatorIndex], nil
+}
+
+func (env *LocalEnvironment) GetMaximumResultSize() int64 {
+	return env.MaximumResultSize
+}
+
+func (env *LocalEnvironment) GetMaximumCalldataOfDataSourceSize() int64 {
+	return env.MaximumCalldataOfDataSourceSize
+}
+
+func (env *LocalEnvironment) RequestExternalData(
+	dataSourceID int64,
+	externalDataID int64,
+	calldata []byte,
+) error {
+	if env.aggregating {
+		return fmt.Errorf("RequestExternalData: not allowed during aggregation")
+	}
+	for _, request := range env.Requests {
+		if request.ExternalID == externalDataID {
+			return fmt.Errorf("RequestExternalData: duplicate external ID %d", externalDataID)
+		}
+	}
+	env.Requests = append(env.Requests, LocalRequest{
+		DataSourceID: dataSourceID,
+		ExternalID:   externalDataID,
+		Calldata:     calldata,
+	})
+	return nil
+}
+
+func (env *LocalEnvironment) GetExternalData(
+	externalDataID int64,
+	validatorIndex int64,
+) ([]byte, uint8, error) {
+	if !env.aggregating {
+		return nil, 0, fmt.Errorf("GetExternalData: not allowed during preparation")
+	}
+	for _, report := range env.Reports {
+		if report.ExternalID == externalDataID && report.Validator == validatorIndex {
+			return []byte(report.Data), report.ExitCode, nil
+		}
+	}
+	return nil, 0, fmt.Errorf(
+		"GetExternalData: no report for external ID %d from validator %d", externalDataID, validatorIndex,
+	)
+}
+
+// GetLatestResult always fails, since there are no earlier results outside of the chain.
+func (env *LocalEnvironment) GetLatestResult(oracleScriptID int64, calldata []byte) ([]byte, error) {
+	return nil, fmt.Errorf("GetLatestResult: no results are available locally")
+}
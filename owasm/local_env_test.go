@@ -0,0 +1,128 @@
+package owasm
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestLocalEnvironment(reports []LocalReport) *LocalEnvironment {
+	return &LocalEnvironment{
+		RequestID:                       1,
+		ValidatorAddresses:              [][]byte{[]byte("validator1"), []byte("validator2")},
+		SufficientValidatorCount:        2,
+		MaximumResultSize:               1024,
+		MaximumCalldataOfDataSourceSize: 1024,
+		Reports:                         reports,
+	}
+}
+
+func TestReadLocalReports(t *testing.T) {
+	dir, err := ioutil.TempDir("", "owasm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "reports.json")
+	content := `[{"external_id": 7, "validator": 1, "exit_code": 2, "data": "42"}]`
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	reports, err := ReadLocalReports(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := LocalReport{ExternalID: 7, Validator: 1, ExitCode: 2, Data: "42"}
+	if len(reports) != 1 || reports[0] != expected {
+		t.Errorf("reports %+v, expected [%+v]", reports, expected)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadLocalReports(path); err == nil {
+		t.Error("expected an invalid reports file to be rejected")
+	}
+}
+
+func TestLocalEnvironmentCalldataAndReportRoundTrip(t *testing.T) {
+	env := newTestLocalEnvironment([]LocalReport{
+		{ExternalID: 7, Validator: 0, ExitCode: 0, Data: "first"},
+		{ExternalID: 7, Validator: 1, ExitCode: 3, Data: "second"},
+	})
+	calldata := []byte("BTC/USD")
+	r := NewResolver(env, calldata, DefaultGasSchedule(), nil)
+	inst := newTestInstance()
+	call := func(field string, args ...int64) int64 {
+		return r.ResolveFunc("env", field)(inst, args)
+	}
+
+	// The prepare phase reads its calldata and passes it on to a data source.
+	if status := call("readCallData", 0, 0, int64(len(calldata))); status != 0 {
+		t.Fatalf("readCallData returned %d", status)
+	}
+	if status := call("requestExternalData", 5, 7, 0, int64(len(calldata))); status != 0 {
+		t.Fatalf("requestExternalData returned %d", status)
+	}
+	if status := call("requestExternalData", 5, 7, 0, int64(len(calldata))); status != -1 {
+		t.Errorf("requestExternalData with a duplicate external ID returned %d, expected -1", status)
+	}
+	expected := LocalRequest{DataSourceID: 5, ExternalID: 7, Calldata: calldata}
+	if len(env.Requests) != 1 || env.Requests[0].DataSourceID != expected.DataSourceID ||
+		env.Requests[0].ExternalID != expected.ExternalID || !bytes.Equal(env.Requests[0].Calldata, calldata) {
+		t.Errorf("requests %+v, expected [%+v]", env.Requests, expected)
+	}
+	if count := env.GetReceivedValidatorCount(); count != 0 {
+		t.Errorf("received validator count %d during preparation, expected 0", count)
+	}
+
+	// The execute phase reads the reports of each validator for the external ID.
+	env.SetAggregating(true)
+	if count := env.GetReceivedValidatorCount(); count != 2 {
+		t.Errorf("received validator count %d, expected 2", count)
+	}
+	if status := call("requestExternalData", 5, 8, 0, 1); status != -1 {
+		t.Errorf("requestExternalData during aggregation returned %d, expected -1", status)
+	}
+	for validator, report := range env.Reports {
+		if size := call("getExternalDataSize", 7, int64(validator)); size != int64(len(report.Data)) {
+			t.Errorf("validator %d: external data size %d, expected %d", validator, size, len(report.Data))
+		}
+		if code := call("getExternalDataStatusCode", 7, int64(validator)); code != int64(report.ExitCode) {
+			t.Errorf("validator %d: status code %d, expected %d", validator, code, report.ExitCode)
+		}
+		if status := call("readExternalData", 7, int64(validator), 100, 0, int64(len(report.Data))); status != 0 {
+			t.Fatalf("validator %d: readExternalData returned %d", validator, status)
+		}
+		if data := inst.Memory()[100 : 100+len(report.Data)]; string(data) != report.Data {
+			t.Errorf("validator %d: read %q, expected %q", validator, data, report.Data)
+		}
+	}
+}
+
+func TestLocalEnvironmentMissingExternalData(t *testing.T) {
+	env := newTestLocalEnvironment([]LocalReport{{ExternalID: 7, Validator: 0, Data: "first"}})
+	if _, _, err := env.GetExternalData(7, 0); err == nil {
+		t.Error("expected reading reports during preparation to fail")
+	}
+
+	env.SetAggregating(true)
+	if _, _, err := env.GetExternalData(8, 0); err == nil {
+		t.Error("expected an unknown external ID to fail")
+	}
+	if _, _, err := env.GetExternalData(7, 1); err == nil {
+		t.Error("expected a validator without a report to fail")
+	}
+
+	r := NewResolver(env, []byte{}, DefaultGasSchedule(), nil)
+	inst := newTestInstance()
+	if size := r.ResolveFunc("env", "getExternalDataSize")(inst, []int64{8, 0}); size != -1 {
+		t.Errorf("external data size %d for an unknown external ID, expected -1", size)
+	}
+	if status := r.ResolveFunc("env", "readExternalData")(inst, []int64{8, 0, 0, 0, 1}); status != -1 {
+		t.Errorf("readExternalData returned %d for an unknown external ID, expected -1", status)
+	}
+}
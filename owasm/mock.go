@@ -16,6 +16,9 @@ type mockExecutionEnvironment struct {
 	maximumResultSize                 int64
 	maximumCalldataOfDataSourceSize   int64
 	requestExternalDataResultsCounter [][]int64
+	requestedExternalData             []LocalRequest
+	latestResults                     map[string][]byte
+	latestResultReads                 int
 }
 
 func (m *mockExecutionEnvironment) GetCurrentRequestID() int64 {
@@ -59,8 +62,11 @@ func (m *mockExecutionEnvironment) RequestExternalData(
 	externalDataID int64,
 	calldata []byte,
 ) error {
-	// TODO: Figure out how to mock this elegantly.
-	fmt.Printf("RequestExternalData: DataSourceID = %d, ExternalDataID = %d\n", dataSourceID, externalDataID)
+	m.requestedExternalData = append(m.requestedExternalData, LocalRequest{
+		DataSourceID: dataSourceID,
+		ExternalID:   externalDataID,
+		Calldata:     calldata,
+	})
 	return nil
 }
 
@@ -79,3 +85,12 @@ func (m *mockExecutionEnvironment) GetExternalData(
 	m.requestExternalDataResultsCounter[externalDataID][validatorIndex]++
 	return m.externalDataResults[externalDataID][validatorIndex], 0, nil
 }
+
+func (m *mockExecutionEnvironment) GetLatestResult(oracleScriptID int64, calldata []byte) ([]byte, error) {
+	m.latestResultReads++
+	result, ok := m.latestResults[fmt.Sprintf("%d:%x", oracleScriptID, calldata)]
+	if !ok {
+		return nil, fmt.Errorf("no latest result")
+	}
+	return result, nil
+}
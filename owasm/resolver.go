@@ -1,9 +1,9 @@
 package owasm
 
 import (
+	"bytes"
 	"fmt"
-
-	"github.com/perlin-network/life/exec"
+	"math"
 )
 
 type cache struct {
@@ -15,17 +15,54 @@ type cache struct {
 	err            error
 }
 
+// latestResultCache holds the latest result most recently read by the script.
+type latestResultCache struct {
+	isActive       bool
+	oracleScriptID int64
+	calldata       []byte
+	data           []byte
+	err            error
+}
+
 type resolver struct {
-	env       ExecutionEnvironment
-	calldata  []byte
-	result    []byte
-	cachedata cache
+	env          ExecutionEnvironment
+	calldata     []byte
+	hostCosts    map[string]HostCost
+	result       []byte
+	cachedata    cache
+	latestResult latestResultCache
+	tracer       *Tracer
 }
 
-func (r *resolver) ResolveFunc(module, field string) exec.FunctionImport {
+// hostFunction is a host function that is charged according to the given cost.
+type hostFunction func(inst Instance, args []int64, cost HostCost) int64
+
+func (r *resolver) ResolveFunc(module, field string) HostFunction {
 	if module != "env" {
 		panic(fmt.Errorf("ResolveFunc: unknown module: %s", module))
 	}
+	f := r.resolveFunc(field)
+	cost := r.hostCost(field)
+	bound := func(inst Instance, args []int64) int64 {
+		return f(inst, args, cost)
+	}
+	if r.tracer != nil {
+		return traceHostFunction(r.tracer, field, bound)
+	}
+	return bound
+}
+
+// hostCost returns the cost of the given host function from the gas schedule. A schedule that
+// passes Validate lists every host function.
+func (r *resolver) hostCost(field string) HostCost {
+	cost, ok := r.hostCosts[field]
+	if !ok {
+		panic(fmt.Errorf("ResolveFunc: no gas cost for host function: %s", field))
+	}
+	return cost
+}
+
+func (r *resolver) resolveFunc(field string) hostFunction {
 	switch field {
 	case "getCurrentRequestID":
 		return r.resolveGetCurrentRequestID
@@ -55,83 +92,115 @@ func (r *resolver) ResolveFunc(module, field string) exec.FunctionImport {
 		return r.resolveGetExternalDataSize
 	case "readExternalData":
 		return r.resolveReadExternalData
+	case "getLatestResultSize":
+		return r.resolveGetLatestResultSize
+	case "readLatestResult":
+		return r.resolveReadLatestResult
+	case "log":
+		return r.resolveLog
 	default:
 		panic(fmt.Errorf("ResolveFunc: unknown field: %s", field))
 	}
 }
 
-func (r *resolver) ResolveGlobal(module, field string) int64 {
-	panic(fmt.Errorf("ResolveGlobal is not supported by owasm!"))
+// chargeCall charges the base cost of the host function plus its per-byte cost for the given
+// number of bytes moved across the VM boundary. The VM traps if its gas limit is exceeded.
+func (r *resolver) chargeCall(inst Instance, cost HostCost, size int) {
+	inst.ConsumeGas(cost.BaseCost)
+	r.chargeBytes(inst, cost, size)
+}
+
+// chargeBytes charges the per-byte cost of the host function for the given number of bytes.
+func (r *resolver) chargeBytes(inst Instance, cost HostCost, size int) {
+	if size < 0 {
+		panic(fmt.Errorf("chargeBytes: negative data size: %d", size))
+	}
+	if size > 0 && cost.CostPerByte > math.MaxUint64/uint64(size) {
+		panic(fmt.Errorf("chargeBytes: gas overflow"))
+	}
+	inst.ConsumeGas(cost.CostPerByte * uint64(size))
 }
 
-func (r *resolver) resolveGetCurrentRequestID(vm *exec.VirtualMachine) int64 {
+func (r *resolver) resolveGetCurrentRequestID(inst Instance, args []int64, cost HostCost) int64 {
+	r.chargeCall(inst, cost, 0)
 	return r.env.GetCurrentRequestID()
 }
 
-func (r *resolver) resolveGetRequestedValidatorCount(vm *exec.VirtualMachine) int64 {
+func (r *resolver) resolveGetRequestedValidatorCount(inst Instance, args []int64, cost HostCost) int64 {
+	r.chargeCall(inst, cost, 0)
 	return r.env.GetRequestedValidatorCount()
 }
 
-func (r *resolver) resolveGetSufficientValidatorCount(vm *exec.VirtualMachine) int64 {
+func (r *resolver) resolveGetSufficientValidatorCount(inst Instance, args []int64, cost HostCost) int64 {
+	r.chargeCall(inst, cost, 0)
 	return r.env.GetSufficientValidatorCount()
 }
 
-func (r *resolver) resolveGetReceivedValidatorCount(vm *exec.VirtualMachine) int64 {
+func (r *resolver) resolveGetReceivedValidatorCount(inst Instance, args []int64, cost HostCost) int64 {
+	r.chargeCall(inst, cost, 0)
 	return r.env.GetRequestedValidatorCount()
 }
 
-func (r *resolver) resolveGetPrepareBlockTime(vm *exec.VirtualMachine) int64 {
+func (r *resolver) resolveGetPrepareBlockTime(inst Instance, args []int64, cost HostCost) int64 {
+	r.chargeCall(inst, cost, 0)
 	return r.env.GetPrepareBlockTime()
 }
 
-func (r *resolver) resolveGetAggregateBlockTime(vm *exec.VirtualMachine) int64 {
+func (r *resolver) resolveGetAggregateBlockTime(inst Instance, args []int64, cost HostCost) int64 {
+	r.chargeCall(inst, cost, 0)
 	return r.env.GetAggregateBlockTime()
 }
 
-func (r *resolver) resolveReadValidatorAddress(vm *exec.VirtualMachine) int64 {
-	validatorIndex := GetLocalInt64(vm, 0)
-	resultOffset := int(GetLocalInt64(vm, 0))
+func (r *resolver) resolveReadValidatorAddress(inst Instance, args []int64, cost HostCost) int64 {
+	validatorIndex := args[0]
+	resultOffset := int(args[0])
 	address, err := r.env.GetValidatorAddress(validatorIndex)
 	if err != nil {
+		r.chargeCall(inst, cost, 0)
 		return -1
 	}
-	copy(vm.Memory[resultOffset:resultOffset+len(address)], address)
+	r.chargeCall(inst, cost, len(address))
+	copy(inst.Memory()[resultOffset:resultOffset+len(address)], address)
 	return 0
 }
 
-func (r *resolver) resolveGetCallDataSize(vm *exec.VirtualMachine) int64 {
+func (r *resolver) resolveGetCallDataSize(inst Instance, args []int64, cost HostCost) int64 {
+	r.chargeCall(inst, cost, 0)
 	return int64(len(r.calldata))
 }
 
-func (r *resolver) resolveReadCallData(vm *exec.VirtualMachine) int64 {
-	resultOffset := int(GetLocalInt64(vm, 0))
-	seekOffset := int(GetLocalInt64(vm, 1))
-	resultSize := int(GetLocalInt64(vm, 2))
-	copy(vm.Memory[resultOffset:resultOffset+resultSize], r.calldata[seekOffset:seekOffset+resultSize])
+func (r *resolver) resolveReadCallData(inst Instance, args []int64, cost HostCost) int64 {
+	resultOffset := int(args[0])
+	seekOffset := int(args[1])
+	resultSize := int(args[2])
+	r.chargeCall(inst, cost, resultSize)
+	copy(inst.Memory()[resultOffset:resultOffset+resultSize], r.calldata[seekOffset:seekOffset+resultSize])
 	return 0
 }
 
-func (r *resolver) resolveSaveReturnData(vm *exec.VirtualMachine) int64 {
-	dataOffset := int(GetLocalInt64(vm, 0))
-	dataLength := int(GetLocalInt64(vm, 1))
+func (r *resolver) resolveSaveReturnData(inst Instance, args []int64, cost HostCost) int64 {
+	dataOffset := int(args[0])
+	dataLength := int(args[1])
+	r.chargeCall(inst, cost, dataLength)
 	if dataLength > int(r.env.GetMaximumResultSize()) {
 		return -1
 	}
 	r.result = make([]byte, dataLength)
-	copy(r.result, vm.Memory[dataOffset:dataOffset+dataLength])
+	copy(r.result, inst.Memory()[dataOffset:dataOffset+dataLength])
 	return 0
 }
 
-func (r *resolver) resolveRequestExternalData(vm *exec.VirtualMachine) int64 {
-	dataSourceID := GetLocalInt64(vm, 0)
-	externalDataID := GetLocalInt64(vm, 1)
-	dataOffset := int(GetLocalInt64(vm, 2))
-	dataLength := int(GetLocalInt64(vm, 3))
+func (r *resolver) resolveRequestExternalData(inst Instance, args []int64, cost HostCost) int64 {
+	dataSourceID := args[0]
+	externalDataID := args[1]
+	dataOffset := int(args[2])
+	dataLength := int(args[3])
+	r.chargeCall(inst, cost, dataLength)
 	if dataLength > int(r.env.GetMaximumCalldataOfDataSourceSize()) {
 		return -1
 	}
 	data := make([]byte, dataLength)
-	copy(data, vm.Memory[dataOffset:dataOffset+dataLength])
+	copy(data, inst.Memory()[dataOffset:dataOffset+dataLength])
 	err := r.env.RequestExternalData(dataSourceID, externalDataID, data)
 	if err != nil {
 		return -1
@@ -139,11 +208,16 @@ func (r *resolver) resolveRequestExternalData(vm *exec.VirtualMachine) int64 {
 	return 0
 }
 
-func (r *resolver) getExternalDataFromCache(externalDataID int64, validatorIndex int64) ([]byte, uint8, error) {
+// getExternalDataFromCache returns the external data from the one-entry cache, reading it
+// from the environment and charging the per-byte read gas on a cache miss.
+func (r *resolver) getExternalDataFromCache(
+	inst Instance, cost HostCost, externalDataID int64, validatorIndex int64,
+) ([]byte, uint8, error) {
 	if r.cachedata.externalDataID == externalDataID && r.cachedata.validatorIndex == validatorIndex && r.cachedata.isActive {
 		return r.cachedata.data, r.cachedata.statusCode, r.cachedata.err
 	}
 	externalData, statusCode, err := r.env.GetExternalData(externalDataID, validatorIndex)
+	r.chargeBytes(inst, cost, len(externalData))
 	r.cachedata = cache{
 		externalDataID: externalDataID,
 		validatorIndex: validatorIndex,
@@ -156,43 +230,115 @@ func (r *resolver) getExternalDataFromCache(externalDataID int64, validatorIndex
 	return externalData, statusCode, err
 }
 
-func (r *resolver) resolveGetExternalDataStatusCode(vm *exec.VirtualMachine) int64 {
-	externalDataID := GetLocalInt64(vm, 0)
-	validatorIndex := GetLocalInt64(vm, 1)
-	_, statusCode, err := r.getExternalDataFromCache(externalDataID, validatorIndex)
+func (r *resolver) resolveGetExternalDataStatusCode(inst Instance, args []int64, cost HostCost) int64 {
+	externalDataID := args[0]
+	validatorIndex := args[1]
+	r.chargeCall(inst, cost, 0)
+	_, statusCode, err := r.getExternalDataFromCache(inst, cost, externalDataID, validatorIndex)
 	if err != nil {
 		return -1
 	}
 	return int64(statusCode)
 }
 
-func (r *resolver) resolveGetExternalDataSize(vm *exec.VirtualMachine) int64 {
-	externalDataID := GetLocalInt64(vm, 0)
-	validatorIndex := GetLocalInt64(vm, 1)
-	externalData, _, err := r.getExternalDataFromCache(externalDataID, validatorIndex)
+func (r *resolver) resolveGetExternalDataSize(inst Instance, args []int64, cost HostCost) int64 {
+	externalDataID := args[0]
+	validatorIndex := args[1]
+	r.chargeCall(inst, cost, 0)
+	externalData, _, err := r.getExternalDataFromCache(inst, cost, externalDataID, validatorIndex)
 	if err != nil {
 		return -1
 	}
 	return int64(len(externalData))
 }
 
-func (r *resolver) resolveReadExternalData(vm *exec.VirtualMachine) int64 {
-	externalDataID := GetLocalInt64(vm, 0)
-	validatorIndex := GetLocalInt64(vm, 1)
-	resultOffset := int(GetLocalInt64(vm, 2))
-	seekOffset := int(GetLocalInt64(vm, 3))
-	resultSize := int(GetLocalInt64(vm, 4))
-	externalData, _, err := r.getExternalDataFromCache(externalDataID, validatorIndex)
+func (r *resolver) resolveReadExternalData(inst Instance, args []int64, cost HostCost) int64 {
+	externalDataID := args[0]
+	validatorIndex := args[1]
+	resultOffset := int(args[2])
+	seekOffset := int(args[3])
+	resultSize := int(args[4])
+	r.chargeCall(inst, cost, resultSize)
+	externalData, _, err := r.getExternalDataFromCache(inst, cost, externalDataID, validatorIndex)
+	if err != nil {
+		return -1
+	}
+	copy(inst.Memory()[resultOffset:resultOffset+resultSize], externalData[seekOffset:seekOffset+resultSize])
+	return 0
+}
+
+// getLatestResultFromCache returns the latest result of the oracle script and calldata from the
+// one-entry cache, reading it from the environment and charging the per-byte read gas on a cache
+// miss. It fails without reading if the calldata is larger than a data source calldata may be.
+func (r *resolver) getLatestResultFromCache(
+	inst Instance, cost HostCost, oracleScriptID int64, calldataOffset int, calldataLength int,
+) ([]byte, error) {
+	if calldataLength > int(r.env.GetMaximumCalldataOfDataSourceSize()) {
+		return nil, fmt.Errorf("getLatestResultFromCache: calldata size %d is too large", calldataLength)
+	}
+	calldata := inst.Memory()[calldataOffset : calldataOffset+calldataLength]
+	if r.latestResult.isActive && r.latestResult.oracleScriptID == oracleScriptID &&
+		bytes.Equal(r.latestResult.calldata, calldata) {
+		return r.latestResult.data, r.latestResult.err
+	}
+	data, err := r.env.GetLatestResult(oracleScriptID, append([]byte{}, calldata...))
+	r.chargeBytes(inst, cost, len(data))
+	r.latestResult = latestResultCache{
+		isActive:       true,
+		oracleScriptID: oracleScriptID,
+		calldata:       append([]byte{}, calldata...),
+		data:           data,
+		err:            err,
+	}
+	return data, err
+}
+
+func (r *resolver) resolveGetLatestResultSize(inst Instance, args []int64, cost HostCost) int64 {
+	oracleScriptID := args[0]
+	calldataOffset := int(args[1])
+	calldataLength := int(args[2])
+	r.chargeCall(inst, cost, calldataLength)
+	data, err := r.getLatestResultFromCache(inst, cost, oracleScriptID, calldataOffset, calldataLength)
+	if err != nil {
+		return -1
+	}
+	return int64(len(data))
+}
+
+func (r *resolver) resolveReadLatestResult(inst Instance, args []int64, cost HostCost) int64 {
+	oracleScriptID := args[0]
+	calldataOffset := int(args[1])
+	calldataLength := int(args[2])
+	resultOffset := int(args[3])
+	seekOffset := int(args[4])
+	resultSize := int(args[5])
+	r.chargeCall(inst, cost, calldataLength+resultSize)
+	data, err := r.getLatestResultFromCache(inst, cost, oracleScriptID, calldataOffset, calldataLength)
 	if err != nil {
 		return -1
 	}
-	copy(vm.Memory[resultOffset:resultOffset+resultSize], externalData[seekOffset:seekOffset+resultSize])
+	copy(inst.Memory()[resultOffset:resultOffset+resultSize], data[seekOffset:seekOffset+resultSize])
+	return 0
+}
+
+// resolveLog records a debug message to the tracer. The message is charged and bounds checked
+// like any other copy so that the script behaves the same whether or not it is traced.
+func (r *resolver) resolveLog(inst Instance, args []int64, cost HostCost) int64 {
+	dataOffset := int(args[0])
+	dataLength := int(args[1])
+	r.chargeCall(inst, cost, dataLength)
+	message := inst.Memory()[dataOffset : dataOffset+dataLength]
+	if r.tracer != nil {
+		r.tracer.Logs = append(r.tracer.Logs, string(message))
+	}
 	return 0
 }
 
-func NewResolver(env ExecutionEnvironment, calldata []byte) *resolver {
+func NewResolver(env ExecutionEnvironment, calldata []byte, schedule GasSchedule, tracer *Tracer) *resolver {
 	return &resolver{
-		env:      env,
-		calldata: calldata,
+		env:       env,
+		calldata:  calldata,
+		hostCosts: schedule.hostCostTable(),
+		tracer:    tracer,
 	}
 }
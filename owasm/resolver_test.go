@@ -0,0 +1,217 @@
+package owasm
+
+import (
+	"bytes"
+	"testing"
+)
+
+// testInstance is an Instance that only meters gas and exposes memory, so that host functions
+// can be called directly without compiling a script.
+type testInstance struct {
+	gasUsed  uint64
+	gasLimit uint64
+	memory   []byte
+}
+
+func newTestInstance() *testInstance {
+	return &testInstance{gasLimit: conformanceGasLimit, memory: make([]byte, 1024)}
+}
+
+func (inst *testInstance) Call(entry string) error {
+	return nil
+}
+
+func (inst *testInstance) GasUsed() uint64 {
+	return inst.gasUsed
+}
+
+func (inst *testInstance) ConsumeGas(amount uint64) {
+	inst.gasUsed += amount
+	if inst.gasUsed > inst.gasLimit {
+		panic(ErrOutOfGas)
+	}
+}
+
+func (inst *testInstance) Memory() []byte {
+	return inst.memory
+}
+
+// callHostFunction calls the given host function with a fresh instance and returns the gas
+// that the call consumed.
+func callHostFunction(r *resolver, field string, args ...int64) uint64 {
+	inst := newTestInstance()
+	r.ResolveFunc("env", field)(inst, args)
+	return inst.GasUsed()
+}
+
+func TestResolverChargesHostCostPerFunction(t *testing.T) {
+	schedule := DefaultGasSchedule()
+	costs := schedule.hostCostTable()
+	env := newConformanceEnvironment()
+	calldata := []byte("calldata")
+
+	// Each call moves the given number of bytes across the VM boundary.
+	calls := []struct {
+		field string
+		args  []int64
+		size  uint64
+	}{
+		{"getCurrentRequestID", nil, 0},
+		{"getRequestedValidatorCount", nil, 0},
+		{"getSufficientValidatorCount", nil, 0},
+		{"getReceivedValidatorCount", nil, 0},
+		{"getPrepareBlockTime", nil, 0},
+		{"getAggregateBlockTime", nil, 0},
+		{"readValidatorAddress", []int64{1}, uint64(len("validator2"))},
+		{"getCallDataSize", nil, 0},
+		{"readCallData", []int64{0, 2, 4}, 4},
+		{"saveReturnData", []int64{0, 16}, 16},
+		{"requestExternalData", []int64{1, 0, 0, 8}, 8},
+		{"getExternalDataStatusCode", []int64{1, 0}, 1},
+		{"getExternalDataSize", []int64{1, 1}, 1},
+		{"readExternalData", []int64{0, 1, 0, 0, 1}, 1 + 1},
+		// The latest result of oracle script 3 for eight zero bytes of calldata is "12345".
+		{"getLatestResultSize", []int64{3, 0, 8}, 8 + 5},
+		{"readLatestResult", []int64{3, 0, 8, 100, 0, 5}, 8 + 5 + 5},
+		{"log", []int64{0, 32}, 32},
+	}
+	if len(calls) != len(costs) {
+		t.Fatalf("expected a call for each of the %d host functions, got %d", len(costs), len(calls))
+	}
+	for _, call := range calls {
+		cost, ok := costs[call.field]
+		if !ok {
+			t.Fatalf("%s: missing from the default gas schedule", call.field)
+		}
+		r := NewResolver(env, calldata, schedule, nil)
+		expected := cost.BaseCost + cost.CostPerByte*call.size
+		if gasUsed := callHostFunction(r, call.field, call.args...); gasUsed != expected {
+			t.Errorf("%s: gas used %d, expected %d", call.field, gasUsed, expected)
+		}
+	}
+}
+
+func TestResolverChargesCopiedBytes(t *testing.T) {
+	schedule := DefaultGasSchedule()
+	cost := schedule.hostCostTable()["readCallData"]
+	calldata := bytes.Repeat([]byte{0x42}, 100)
+	r := NewResolver(newConformanceEnvironment(), calldata, schedule, nil)
+
+	small := callHostFunction(r, "readCallData", 0, 0, 10)
+	large := callHostFunction(r, "readCallData", 0, 0, 100)
+	if large-small != 90*cost.CostPerByte {
+		t.Errorf("reading 90 more bytes cost %d more gas, expected %d", large-small, 90*cost.CostPerByte)
+	}
+}
+
+func TestResolverChargesExternalDataReadOnce(t *testing.T) {
+	schedule := DefaultGasSchedule()
+	cost := schedule.hostCostTable()["getExternalDataSize"]
+	r := NewResolver(newConformanceEnvironment(), []byte{}, schedule, nil)
+	inst := newTestInstance()
+	getExternalDataSize := r.ResolveFunc("env", "getExternalDataSize")
+
+	getExternalDataSize(inst, []int64{0, 1})
+	if inst.GasUsed() != cost.BaseCost+cost.CostPerByte {
+		t.Errorf("first read: gas used %d, expected %d", inst.GasUsed(), cost.BaseCost+cost.CostPerByte)
+	}
+	// The second call hits the one-entry cache, so the data is not charged again.
+	getExternalDataSize(inst, []int64{0, 1})
+	if inst.GasUsed() != 2*cost.BaseCost+cost.CostPerByte {
+		t.Errorf("cached read: gas used %d, expected %d", inst.GasUsed(), 2*cost.BaseCost+cost.CostPerByte)
+	}
+}
+
+func TestResolverReadsLatestResult(t *testing.T) {
+	env := newConformanceEnvironment()
+	r := NewResolver(env, []byte{}, DefaultGasSchedule(), nil)
+	inst := newTestInstance()
+	getLatestResultSize := r.ResolveFunc("env", "getLatestResultSize")
+	readLatestResult := r.ResolveFunc("env", "readLatestResult")
+
+	if size := getLatestResultSize(inst, []int64{3, 0, 8}); size != 5 {
+		t.Fatalf("latest result size %d, expected 5", size)
+	}
+	if readLatestResult(inst, []int64{3, 0, 8, 100, 1, 3}) != 0 {
+		t.Fatal("expected reading the latest result to succeed")
+	}
+	if !bytes.Equal(inst.Memory()[100:103], []byte("234")) {
+		t.Errorf("read %q, expected \"234\"", inst.Memory()[100:103])
+	}
+	if env.latestResultReads != 1 {
+		t.Errorf("read the latest result %d times, expected the one-entry cache to be used", env.latestResultReads)
+	}
+
+	// Other calldata has no result.
+	inst.Memory()[0] = 1
+	if size := getLatestResultSize(inst, []int64{3, 0, 8}); size != -1 {
+		t.Errorf("latest result size %d for unknown calldata, expected -1", size)
+	}
+	if readLatestResult(inst, []int64{4, 0, 8, 100, 0, 1}) != -1 {
+		t.Error("expected reading an unknown latest result to fail")
+	}
+	// Calldata larger than a data source may take is rejected without reading the state.
+	env.maximumCalldataOfDataSourceSize = 4
+	reads := env.latestResultReads
+	if size := getLatestResultSize(inst, []int64{3, 0, 8}); size != -1 || env.latestResultReads != reads {
+		t.Errorf("latest result size %d with oversized calldata, expected -1 without a read", size)
+	}
+}
+
+func TestResolverRejectsUnlistedHostFunction(t *testing.T) {
+	schedule := DefaultGasSchedule()
+	schedule.HostCosts = schedule.HostCosts[1:]
+	r := NewResolver(newConformanceEnvironment(), []byte("calldata"), schedule, nil)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected resolving a host function without a cost to panic")
+		}
+	}()
+	r.ResolveFunc("env", DefaultGasSchedule().HostCosts[0].Function)
+}
+
+func TestResolverTrapsOnOutOfGas(t *testing.T) {
+	schedule := DefaultGasSchedule()
+	r := NewResolver(newConformanceEnvironment(), []byte{}, schedule, nil)
+	inst := newTestInstance()
+	inst.gasLimit = schedule.hostCostTable()["requestExternalData"].BaseCost - 1
+
+	defer func() {
+		if recover() != ErrOutOfGas {
+			t.Error("expected the call to trap with ErrOutOfGas")
+		}
+	}()
+	r.ResolveFunc("env", "requestExternalData")(inst, []int64{1, 0, 0, 8})
+}
+
+func TestGasScheduleValidateHostCosts(t *testing.T) {
+	schedule := DefaultGasSchedule()
+	if err := schedule.Validate(); err != nil {
+		t.Fatalf("default gas schedule is invalid: %v", err)
+	}
+
+	unknown := DefaultGasSchedule()
+	unknown.HostCosts = append(unknown.HostCosts, HostCost{Function: "zzz", BaseCost: 1})
+	if unknown.Validate() == nil {
+		t.Error("expected an unknown host function to be rejected")
+	}
+
+	missing := DefaultGasSchedule()
+	missing.HostCosts = missing.HostCosts[1:]
+	if missing.Validate() == nil {
+		t.Error("expected a schedule without every host function to be rejected")
+	}
+
+	unsorted := DefaultGasSchedule()
+	unsorted.HostCosts[0], unsorted.HostCosts[1] = unsorted.HostCosts[1], unsorted.HostCosts[0]
+	if unsorted.Validate() == nil {
+		t.Error("expected unsorted host functions to be rejected")
+	}
+
+	changed := DefaultGasSchedule()
+	changed.HostCosts[0].BaseCost++
+	if changed.Hash() == schedule.Hash() {
+		t.Error("expected a host cost change to change the schedule hash")
+	}
+}
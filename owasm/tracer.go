@@ -0,0 +1,100 @@
+package owasm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HostCall is a single call from an Owasm script into a host function.
+type HostCall struct {
+	Function  string
+	Args      []int64
+	Result    int64
+	GasBefore uint64
+	GasAfter  uint64
+	Trapped   bool
+}
+
+// Tracer records the host function calls, debug logs, and trap reason of an Owasm execution.
+// Tracing is meant for debugging only and never changes the result or gas usage of a script.
+type Tracer struct {
+	HostCalls  []HostCall
+	Logs       []string
+	GasUsed    uint64
+	TrapReason string
+}
+
+// String returns a human readable summary of the trace.
+func (t *Tracer) String() string {
+	var b strings.Builder
+	for _, line := range t.lines() {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	b.WriteString(t.footer())
+	return b.String()
+}
+
+// Truncated returns the summary of the trace like String, but with at most maxLines lines of host
+// calls and logs that take at most maxBytes bytes in total. The script controls how much it calls
+// and logs, so this bounds the summary wherever it is sent. The gas used and trap reason are
+// always kept.
+func (t *Tracer) Truncated(maxLines int, maxBytes int) string {
+	var b strings.Builder
+	lines := t.lines()
+	for i, line := range lines {
+		if i == maxLines || b.Len()+len(line)+1 > maxBytes {
+			fmt.Fprintf(&b, "... %d more lines\n", len(lines)-i)
+			break
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	b.WriteString(t.footer())
+	return b.String()
+}
+
+// lines returns a line for each host call followed by a line for each debug log.
+func (t *Tracer) lines() []string {
+	lines := make([]string, 0, len(t.HostCalls)+len(t.Logs))
+	for _, call := range t.HostCalls {
+		line := fmt.Sprintf("%s%v -> %d (gas %d -> %d)", call.Function, call.Args, call.Result, call.GasBefore, call.GasAfter)
+		if call.Trapped {
+			line += " trapped"
+		}
+		lines = append(lines, line)
+	}
+	for _, log := range t.Logs {
+		lines = append(lines, fmt.Sprintf("log: %s", log))
+	}
+	return lines
+}
+
+// footer returns the gas used and, if the script trapped, the trap reason.
+func (t *Tracer) footer() string {
+	footer := fmt.Sprintf("gas used: %d", t.GasUsed)
+	if t.TrapReason != "" {
+		footer += fmt.Sprintf("\ntrap: %s", t.TrapReason)
+	}
+	return footer
+}
+
+// traceHostFunction wraps the given host function to record each of its calls to the tracer.
+func traceHostFunction(tracer *Tracer, name string, f HostFunction) HostFunction {
+	return func(inst Instance, args []int64) (result int64) {
+		call := HostCall{
+			Function:  name,
+			Args:      append([]int64(nil), args...),
+			GasBefore: inst.GasUsed(),
+			Trapped:   true,
+		}
+		defer func() {
+			call.Result = result
+			call.GasAfter = inst.GasUsed()
+			tracer.HostCalls = append(tracer.HostCalls, call)
+		}()
+		result = f(inst, args)
+		call.Trapped = false
+		return result
+	}
+}
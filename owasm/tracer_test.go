@@ -0,0 +1,139 @@
+package owasm
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestTracerRecordsHostCallsAndLogs(t *testing.T) {
+	tracer := &Tracer{}
+	traced := NewResolver(newConformanceEnvironment(), []byte("calldata"), DefaultGasSchedule(), tracer)
+	untraced := NewResolver(newConformanceEnvironment(), []byte("calldata"), DefaultGasSchedule(), nil)
+	tracedInst := newTestInstance()
+	untracedInst := newTestInstance()
+	message := []byte("hello")
+	copy(tracedInst.Memory(), message)
+	copy(untracedInst.Memory(), message)
+
+	calls := []struct {
+		field string
+		args  []int64
+	}{
+		{"getCurrentRequestID", nil},
+		{"log", []int64{0, int64(len(message))}},
+		{"getCallDataSize", nil},
+	}
+	for _, call := range calls {
+		traced.ResolveFunc("env", call.field)(tracedInst, call.args)
+		untraced.ResolveFunc("env", call.field)(untracedInst, call.args)
+	}
+
+	if tracedInst.GasUsed() != untracedInst.GasUsed() {
+		t.Errorf("traced run used %d gas, untraced run %d", tracedInst.GasUsed(), untracedInst.GasUsed())
+	}
+	if len(tracer.HostCalls) != len(calls) {
+		t.Fatalf("%d host calls traced, expected %d", len(tracer.HostCalls), len(calls))
+	}
+	for i, call := range calls {
+		traced := tracer.HostCalls[i]
+		if traced.Function != call.field || traced.Trapped {
+			t.Errorf("call %d: traced %+v, expected %s without a trap", i, traced, call.field)
+		}
+	}
+	if last := tracer.HostCalls[2]; last.Result != int64(len("calldata")) || last.GasBefore >= last.GasAfter {
+		t.Errorf("getCallDataSize traced as %+v", last)
+	}
+	if len(tracer.Logs) != 1 || tracer.Logs[0] != "hello" {
+		t.Errorf("logs %q, expected [hello]", tracer.Logs)
+	}
+}
+
+func TestTracerRecordsTrappedHostCall(t *testing.T) {
+	tracer := &Tracer{}
+	r := NewResolver(newConformanceEnvironment(), []byte{}, DefaultGasSchedule(), tracer)
+	inst := newTestInstance()
+	inst.gasLimit = 1
+
+	func() {
+		defer func() {
+			if recover() != ErrOutOfGas {
+				t.Error("expected the call to trap with ErrOutOfGas")
+			}
+		}()
+		r.ResolveFunc("env", "getCurrentRequestID")(inst, nil)
+	}()
+	if len(tracer.HostCalls) != 1 || !tracer.HostCalls[0].Trapped {
+		t.Errorf("host calls %+v, expected a trapped call", tracer.HostCalls)
+	}
+}
+
+func TestExecuteWithTracer(t *testing.T) {
+	code, err := ioutil.ReadFile("res/silly.wasm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, gasUsed, err := Execute(
+		newConformanceEnvironment(), code, "prepare", []byte{}, conformanceGasLimit, DefaultLimits, DefaultGasSchedule(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tracer := &Tracer{}
+	tracedResult, tracedGasUsed, err := ExecuteWithTracer(
+		newConformanceEnvironment(), code, "prepare", []byte{}, conformanceGasLimit, DefaultLimits,
+		DefaultGasSchedule(), tracer,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(result, tracedResult) || gasUsed != tracedGasUsed {
+		t.Errorf("traced run returned %x with %d gas, untraced %x with %d gas", tracedResult, tracedGasUsed, result, gasUsed)
+	}
+	if tracer.GasUsed != gasUsed || tracer.TrapReason != "" {
+		t.Errorf("tracer gas used %d with trap %q, expected %d without a trap", tracer.GasUsed, tracer.TrapReason, gasUsed)
+	}
+	requests := 0
+	for _, call := range tracer.HostCalls {
+		if call.Function == "requestExternalData" {
+			requests++
+		}
+	}
+	if requests != 2 {
+		t.Errorf("%d external data requests traced, expected 2", requests)
+	}
+
+	tracer = &Tracer{}
+	if _, _, err := ExecuteWithTracer(
+		newConformanceEnvironment(), code, "prepare", []byte{}, 1000, DefaultLimits, DefaultGasSchedule(), tracer,
+	); err != ErrOutOfGas {
+		t.Fatalf("expected the run to fail with ErrOutOfGas, got %v", err)
+	}
+	if tracer.TrapReason != ErrOutOfGas.Error() {
+		t.Errorf("trap reason %q, expected %q", tracer.TrapReason, ErrOutOfGas.Error())
+	}
+}
+
+func TestTracerTruncated(t *testing.T) {
+	tracer := &Tracer{GasUsed: 7, TrapReason: "out of gas"}
+	for i := 0; i < 10; i++ {
+		tracer.Logs = append(tracer.Logs, fmt.Sprintf("message %d", i))
+	}
+	if truncated := tracer.Truncated(100, 1000); truncated != tracer.String() {
+		t.Errorf("trace within the limits was truncated:\n%s", truncated)
+	}
+
+	expected := "log: message 0\nlog: message 1\nlog: message 2\n... 7 more lines\ngas used: 7\ntrap: out of gas"
+	if truncated := tracer.Truncated(3, 1000); truncated != expected {
+		t.Errorf("truncated to 3 lines:\n%s\nexpected:\n%s", truncated, expected)
+	}
+
+	tracer.Logs = []string{strings.Repeat("x", 100)}
+	expected = "... 1 more lines\ngas used: 7\ntrap: out of gas"
+	if truncated := tracer.Truncated(100, 50); truncated != expected {
+		t.Errorf("truncated to 50 bytes:\n%s\nexpected:\n%s", truncated, expected)
+	}
+}
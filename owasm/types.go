@@ -57,4 +57,12 @@ type ExecutionEnvironment interface {
 		externalDataID int64,
 		validatorIndex int64,
 	) ([]byte, uint8, error)
+
+	// GetLatestResult reads from the execution environment state the result data
+	// of the latest request to the specified oracle script with the specified
+	// calldata that produced a result.
+	GetLatestResult(
+		oracleScriptID int64,
+		calldata []byte,
+	) ([]byte, error)
 }
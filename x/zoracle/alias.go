@@ -11,6 +11,8 @@ const (
 	RouterKey         = types.RouterKey
 	StoreKey          = types.StoreKey
 
+	ReferenceDataAccountName = types.ReferenceDataAccountName
+
 	EventTypeCreateDataSource   = types.EventTypeCreateDataSource
 	EventTypeEditDataSource     = types.EventTypeEditDataSource
 	EventTypeCreateOracleScript = types.EventTypeCreateOracleScript
@@ -37,6 +39,10 @@ var (
 	NewMsgAddOracleAddress     = types.NewMsgAddOracleAddress
 	NewMsgRemoveOracleAdderess = types.NewMsgRemoveOracleAdderess
 	NewOraclePacketData        = types.NewOraclePacketData
+	NewReferenceSymbol         = types.NewReferenceSymbol
+	NewMsgSubscribe            = types.NewMsgSubscribe
+	NewMsgCancelRequest        = types.NewMsgCancelRequest
+	NewQueryRequestsParams     = types.NewQueryRequestsParams
 
 	RequestStoreKey      = types.RequestStoreKey
 	ResultStoreKey       = types.ResultStoreKey
@@ -57,13 +63,19 @@ var (
 	KeyMaxRawDataReportSize         = types.KeyMaxRawDataReportSize
 	KeyMaxResultSize                = types.KeyMaxResultSize
 
-	QueryRequestByID    = types.QueryRequestByID
-	QueryRequests       = types.QueryRequests
-	QueryPending        = types.QueryPending
-	QueryRequestNumber  = types.QueryRequestNumber
-	QueryDataSourceByID = types.QueryDataSourceByID
-	QueryDataSources    = types.QueryDataSources
-	QueryOracleScripts  = types.QueryOracleScripts
+	QueryRequestByID         = types.QueryRequestByID
+	QueryRequests            = types.QueryRequests
+	QueryPending             = types.QueryPending
+	QueryRequestNumber       = types.QueryRequestNumber
+	QueryDataSourceByID      = types.QueryDataSourceByID
+	QueryDataSources         = types.QueryDataSources
+	QueryOracleScripts       = types.QueryOracleScripts
+	QueryLatestResult        = types.QueryLatestResult
+	QueryReferenceData       = types.QueryReferenceData
+	QuerySubscriptionByID    = types.QuerySubscriptionByID
+	QueryRequestsByRequester = types.QueryRequestsByRequester
+	QuerySearchRequests      = types.QuerySearchRequests
+	QueryPendingAssignments  = types.QueryPendingAssignments
 
 	ParamKeyTable = keeper.ParamKeyTable
 )
@@ -78,6 +90,8 @@ type (
 	MsgEditOracleScript     = types.MsgEditOracleScript
 	MsgAddOracleAddress     = types.MsgAddOracleAddress
 	MsgRemoveOracleAdderess = types.MsgRemoveOracleAdderess
+	MsgSubscribe            = types.MsgSubscribe
+	MsgCancelRequest        = types.MsgCancelRequest
 	OraclePacketData        = types.OraclePacketData
 
 	RawDataReport         = types.RawDataReport
@@ -85,11 +99,25 @@ type (
 	RequestQuerierInfo    = types.RequestQuerierInfo
 	DataSourceQuerierInfo = types.DataSourceQuerierInfo
 
+	LatestResultQuerierInfo = types.LatestResultQuerierInfo
+	QueryRequestsParams     = types.QueryRequestsParams
+	RequestSearchResult     = types.RequestSearchResult
+
+	PendingAssignmentQuerierInfo = types.PendingAssignmentQuerierInfo
+	Result                       = types.Result
+	Params                       = types.Params
+
+	ReferenceSymbol  = types.ReferenceSymbol
+	ReferenceSymbols = types.ReferenceSymbols
+	ReferenceData    = types.ReferenceData
+
 	RequestID      = types.RequestID
 	OracleScriptID = types.OracleScriptID
 	ExternalID     = types.ExternalID
 	DataSourceID   = types.DataSourceID
+	SubscriptionID = types.SubscriptionID
 
 	DataSource   = types.DataSource
 	OracleScript = types.OracleScript
+	Subscription = types.Subscription
 )
@@ -8,7 +8,24 @@ import (
 	"github.com/cosmos/cosmos-sdk/client/context"
 	"github.com/cosmos/cosmos-sdk/client/flags"
 	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/spf13/cobra"
+
+	"github.com/cosmos/gaia/x/zoracle/client/utils"
+)
+
+const (
+	flagProve          = "prove"
+	flagStartID        = "start-id"
+	flagLimit          = "limit"
+	flagOracleScriptID = "oracle-script-id"
+	flagRequester      = "requester"
+	flagStatus         = "status"
+	flagValidator      = "validator"
+	flagMinHeight      = "min-height"
+	flagMaxHeight      = "max-height"
+	flagSourcePort     = "source-port"
+	flagSourceChannel  = "source-channel"
 )
 
 // GetQueryCmd returns
@@ -22,7 +39,14 @@ func GetQueryCmd(storeKey string, cdc *codec.Codec) *cobra.Command {
 	}
 	zoracleCmd.AddCommand(flags.GetCommands(
 		GetCmdReadRequest(storeKey, cdc),
+		GetCmdResult(storeKey, cdc),
 		GetCmdPendingRequest(storeKey, cdc),
+		GetCmdParams(storeKey, cdc),
+		GetCmdLatestResult(storeKey, cdc),
+		GetCmdReferenceData(storeKey, cdc),
+		GetCmdSubscription(storeKey, cdc),
+		GetCmdRequestsByRequester(storeKey, cdc),
+		GetCmdSearchRequests(storeKey, cdc),
 	)...)
 
 	return zoracleCmd
@@ -51,6 +75,31 @@ func GetCmdReadRequest(queryRoute string, cdc *codec.Codec) *cobra.Command {
 	}
 }
 
+// GetCmdResult queries the result of a request, optionally with its Merkle proof
+func GetCmdResult(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "result [request-id]",
+		Short: "Query the result of a request, with the IAVL proof of its store entry if --prove is set",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			prove, err := cmd.Flags().GetBool(flagProve)
+			if err != nil {
+				return err
+			}
+
+			out, err := utils.QueryResult(cliCtx, queryRoute, args[0], prove)
+			if err != nil {
+				return err
+			}
+			return cliCtx.PrintOutput(out)
+		},
+	}
+	cmd.Flags().Bool(flagProve, false, "Include the IAVL proof of the result")
+	return cmd
+}
+
 // GetCmdPendingRequest queries request in pending state
 func GetCmdPendingRequest(queryRoute string, cdc *codec.Codec) *cobra.Command {
 	return &cobra.Command{
@@ -74,3 +123,226 @@ func GetCmdPendingRequest(queryRoute string, cdc *codec.Codec) *cobra.Command {
 		},
 	}
 }
+
+// GetCmdParams queries the current zoracle parameters
+func GetCmdParams(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:  "params",
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			res, _, err := cliCtx.QueryWithData(
+				fmt.Sprintf("custom/%s/%s", queryRoute, types.QueryParams),
+				nil,
+			)
+			if err != nil {
+				return err
+			}
+
+			var out types.Params
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
+
+// GetCmdLatestResult queries the latest result of an oracle script and calldata
+func GetCmdLatestResult(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "latest_result [oracle-script-id] [calldata]",
+		Short: "Query the latest result of an oracle script with the given hex encoded calldata",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			calldata := ""
+			if len(args) == 2 {
+				calldata = args[1]
+			}
+			res, _, err := cliCtx.QueryWithData(
+				fmt.Sprintf("custom/%s/%s/%s/%s", queryRoute, types.QueryLatestResult, args[0], calldata),
+				nil,
+			)
+			if err != nil {
+				return err
+			}
+
+			var out types.LatestResultQuerierInfo
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
+
+// GetCmdReferenceData queries the latest rate of a currency pair
+func GetCmdReferenceData(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "reference_data [base] [quote]",
+		Short: "Query the latest rate of the base currency in terms of the quote currency",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			res, _, err := cliCtx.QueryWithData(
+				fmt.Sprintf("custom/%s/%s/%s/%s", queryRoute, types.QueryReferenceData, args[0], args[1]),
+				nil,
+			)
+			if err != nil {
+				return err
+			}
+
+			var out types.ReferenceData
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
+
+// GetCmdSubscription queries a subscription by its ID
+func GetCmdSubscription(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "subscription [subscription-id]",
+		Short: "Query a subscription with its remaining balance and the height of its next request",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			res, _, err := cliCtx.QueryWithData(
+				fmt.Sprintf("custom/%s/%s/%s", queryRoute, types.QuerySubscriptionByID, args[0]),
+				nil,
+			)
+			if err != nil {
+				return err
+			}
+
+			var out types.Subscription
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
+
+// GetCmdRequestsByRequester queries the requests made by an address
+func GetCmdRequestsByRequester(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "requests_by_requester [address] [start-id] [count]",
+		Short: "Query at most count requests made by an address, starting from the given request ID",
+		Long: `Query at most count requests made by an address, starting from the given request ID, in ascending order.
+Requests made before the requester was recorded on requests are missing from the requester index and are not returned.`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			res, _, err := cliCtx.QueryWithData(
+				fmt.Sprintf(
+					"custom/%s/%s/%s/%s/%s",
+					queryRoute, types.QueryRequestsByRequester, args[0], args[1], args[2],
+				),
+				nil,
+			)
+			if err != nil {
+				return err
+			}
+
+			var out []types.RequestQuerierInfo
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
+
+// GetCmdSearchRequests queries a page of the requests that match the given filters
+func GetCmdSearchRequests(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search_requests",
+		Short: "Search requests by oracle script, requester, status, validator, height range and source channel",
+		Long: `Search requests that match all of the given filters, in ascending request ID order.
+Pass the returned next_id as --start-id to get the next page. A zero next_id means there are no more requests.
+Each page checks a bounded number of requests, so a page can be empty while next_id is not zero.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			startID, err := cmd.Flags().GetInt64(flagStartID)
+			if err != nil {
+				return err
+			}
+			limit, err := cmd.Flags().GetInt(flagLimit)
+			if err != nil {
+				return err
+			}
+			oracleScriptID, err := cmd.Flags().GetInt64(flagOracleScriptID)
+			if err != nil {
+				return err
+			}
+			status, err := cmd.Flags().GetString(flagStatus)
+			if err != nil {
+				return err
+			}
+			minHeight, err := cmd.Flags().GetInt64(flagMinHeight)
+			if err != nil {
+				return err
+			}
+			maxHeight, err := cmd.Flags().GetInt64(flagMaxHeight)
+			if err != nil {
+				return err
+			}
+			sourcePort, err := cmd.Flags().GetString(flagSourcePort)
+			if err != nil {
+				return err
+			}
+			sourceChannel, err := cmd.Flags().GetString(flagSourceChannel)
+			if err != nil {
+				return err
+			}
+
+			var requester sdk.AccAddress
+			if raw, _ := cmd.Flags().GetString(flagRequester); raw != "" {
+				requester, err = sdk.AccAddressFromBech32(raw)
+				if err != nil {
+					return err
+				}
+			}
+			var validator sdk.ValAddress
+			if raw, _ := cmd.Flags().GetString(flagValidator); raw != "" {
+				validator, err = sdk.ValAddressFromBech32(raw)
+				if err != nil {
+					return err
+				}
+			}
+
+			params := types.NewQueryRequestsParams(
+				types.RequestID(startID), limit, types.OracleScriptID(oracleScriptID), requester,
+				status, validator, minHeight, maxHeight, sourcePort, sourceChannel,
+			)
+			if err := params.Validate(); err != nil {
+				return err
+			}
+
+			bz, err := cdc.MarshalJSON(params)
+			if err != nil {
+				return err
+			}
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", queryRoute, types.QuerySearchRequests), bz)
+			if err != nil {
+				return err
+			}
+
+			var out types.RequestSearchResult
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+	cmd.Flags().Int64(flagStartID, 1, "Request ID to start the search from")
+	cmd.Flags().Int(flagLimit, 100, "Maximum number of requests to return, at most 100")
+	cmd.Flags().Int64(flagOracleScriptID, 0, "Only return requests to this oracle script")
+	cmd.Flags().String(flagRequester, "", "Only return requests made by this address, excluding requests made before the requester was recorded")
+	cmd.Flags().String(flagStatus, "", "Only return requests in this resolve status (open, success, failure or cancelled)")
+	cmd.Flags().String(flagValidator, "", "Only return requests assigned to this validator")
+	cmd.Flags().Int64(flagMinHeight, 0, "Only return requests made at or after this height")
+	cmd.Flags().Int64(flagMaxHeight, 0, "Only return requests made at or before this height")
+	cmd.Flags().String(flagSourcePort, "", "Only return requests from this IBC port, along with --source-channel")
+	cmd.Flags().String(flagSourceChannel, "", "Only return requests from this IBC channel, along with --source-port")
+	return cmd
+}
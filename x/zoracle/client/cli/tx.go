@@ -32,6 +32,10 @@ const (
 	flagExpiration               = "expiration"
 	flagPrepareGas               = "prepare-gas"
 	flagExecuteGas               = "execute-gas"
+	flagPriorityFee              = "priority-fee"
+	flagInterval                 = "interval"
+	flagEndHeight                = "end-height"
+	flagDeposit                  = "deposit"
 )
 
 // GetTxCmd returns the transaction commands for this module
@@ -49,6 +53,8 @@ func GetTxCmd(storeKey string, cdc *codec.Codec) *cobra.Command {
 		GetCmdCreateOracleScript(cdc),
 		GetCmdEditOracleScript(cdc),
 		GetCmdRequest(cdc),
+		GetCmdSubscribe(cdc),
+		GetCmdCancelRequest(cdc),
 		GetCmdReport(cdc),
 	)...)
 
@@ -66,8 +72,9 @@ func GetCmdRequest(cdc *codec.Codec) *cobra.Command {
 Example:
 $ %s tx zoracle request 1 source_port source_channel -c 1234abcdef -r 4 -v 3 -x 20 -w 50 -g 5000 --from mykey
 $ %s tx zoracle request 1 source_port source_channel --calldata 1234abcdef --requested-validator-count 4 --sufficient-validator-count 3 --expiration 20 --prepare-gas 50 --execute-gas 5000 --from mykey
+$ %s tx zoracle request 1 source_port source_channel -c 1234abcdef -r 4 -v 3 -x 20 -w 50 -g 5000 --priority-fee 100stake --from mykey
 `,
-				version.ClientName, version.ClientName,
+				version.ClientName, version.ClientName, version.ClientName,
 			),
 		),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -111,6 +118,16 @@ $ %s tx zoracle request 1 source_port source_channel --calldata 1234abcdef --req
 				return err
 			}
 
+			priorityFeeStr, err := cmd.Flags().GetString(flagPriorityFee)
+			if err != nil {
+				return err
+			}
+
+			priorityFee, err := sdk.ParseCoins(priorityFeeStr)
+			if err != nil {
+				return err
+			}
+
 			msg := types.NewMsgRequestData(
 				oracleScriptID,
 				calldata,
@@ -122,6 +139,7 @@ $ %s tx zoracle request 1 source_port source_channel --calldata 1234abcdef --req
 				cliCtx.GetFromAddress(),
 				args[1],
 				args[2],
+				priorityFee,
 			)
 
 			err = msg.ValidateBasic()
@@ -144,6 +162,128 @@ $ %s tx zoracle request 1 source_port source_channel --calldata 1234abcdef --req
 	cmd.MarkFlagRequired(flagPrepareGas)
 	cmd.Flags().Uint64P(flagExecuteGas, "g", 0, "The amount of gas that will be reserved for later execution")
 	cmd.MarkFlagRequired(flagExecuteGas)
+	cmd.Flags().String(flagPriorityFee, "", "Fee paid to validators to resolve this request ahead of others")
+
+	return cmd
+}
+
+// GetCmdSubscribe implements the subscribe command handler.
+func GetCmdSubscribe(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "subscribe [oracle-script-id] (-c [calldata]) (-r [requested-validator-count]) (-v [sufficient-validator-count]) (-x [expiration]) (-w [prepare-gas]) (-g [execute-gas]) (--interval [interval]) (--end-height [end-height]) (--deposit [deposit])",
+		Short: "Request an existing oracle script repeatedly at a fixed block interval",
+		Args:  cobra.ExactArgs(1),
+		Long: strings.TrimSpace(
+			fmt.Sprintf(`Request an existing oracle script every interval blocks until the end height. The gas and the
+data source fees of each request are paid out of the deposit, which must include the bond denomination,
+and the subscription is closed with the remaining deposit refunded once the deposit cannot cover them
+or the end height has passed.
+Example:
+$ %s tx zoracle subscribe 1 -c 1234abcdef -r 4 -v 3 -x 20 -w 50 -g 5000 --interval 10 --end-height 100000 --deposit 1000000stake --from mykey
+`,
+				version.ClientName,
+			),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
+			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(authclient.GetTxEncoder(cdc))
+
+			int64OracleScriptID, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return err
+			}
+			oracleScriptID := types.OracleScriptID(int64OracleScriptID)
+
+			calldata, err := cmd.Flags().GetBytesHex(flagCalldata)
+			if err != nil {
+				return err
+			}
+
+			requestedValidatorCount, err := cmd.Flags().GetInt64(flagRequestedValidatorCount)
+			if err != nil {
+				return err
+			}
+
+			sufficientValidatorCount, err := cmd.Flags().GetInt64(flagSufficientValidatorCount)
+			if err != nil {
+				return err
+			}
+
+			expiration, err := cmd.Flags().GetInt64(flagExpiration)
+			if err != nil {
+				return err
+			}
+
+			prepareGas, err := cmd.Flags().GetUint64(flagPrepareGas)
+			if err != nil {
+				return err
+			}
+
+			executionGas, err := cmd.Flags().GetUint64(flagExecuteGas)
+			if err != nil {
+				return err
+			}
+
+			interval, err := cmd.Flags().GetInt64(flagInterval)
+			if err != nil {
+				return err
+			}
+
+			endHeight, err := cmd.Flags().GetInt64(flagEndHeight)
+			if err != nil {
+				return err
+			}
+
+			depositStr, err := cmd.Flags().GetString(flagDeposit)
+			if err != nil {
+				return err
+			}
+
+			deposit, err := sdk.ParseCoins(depositStr)
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgSubscribe(
+				oracleScriptID,
+				calldata,
+				requestedValidatorCount,
+				sufficientValidatorCount,
+				expiration,
+				prepareGas,
+				executionGas,
+				interval,
+				endHeight,
+				deposit,
+				cliCtx.GetFromAddress(),
+			)
+
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			return authclient.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+
+	cmd.Flags().BytesHexP(flagCalldata, "c", nil, "Calldata used in calling the oracle script")
+	cmd.Flags().Int64P(flagRequestedValidatorCount, "r", 0, "Number of top validators that need to report data for each request")
+	cmd.MarkFlagRequired(flagRequestedValidatorCount)
+	cmd.Flags().Int64P(flagSufficientValidatorCount, "v", 0, "Minimum number of reports sufficient to conclude each request's result")
+	cmd.MarkFlagRequired(flagSufficientValidatorCount)
+	cmd.Flags().Int64P(flagExpiration, "x", 0, "Maximum block count before each data request is considered expired")
+	cmd.MarkFlagRequired(flagExpiration)
+	cmd.Flags().Uint64P(flagPrepareGas, "w", 0, "The amount of gas that will be reserved for prepare function")
+	cmd.MarkFlagRequired(flagPrepareGas)
+	cmd.Flags().Uint64P(flagExecuteGas, "g", 0, "The amount of gas that will be reserved for later execution")
+	cmd.MarkFlagRequired(flagExecuteGas)
+	cmd.Flags().Int64(flagInterval, 0, "Number of blocks between requests")
+	cmd.MarkFlagRequired(flagInterval)
+	cmd.Flags().Int64(flagEndHeight, 0, "Block height after which no more requests are made")
+	cmd.MarkFlagRequired(flagEndHeight)
+	cmd.Flags().String(flagDeposit, "", "Prepaid balance used to pay the gas and data source fees of the requests")
 
 	return cmd
 }
@@ -209,6 +349,42 @@ $ %s tx zoracle report 1 1:172.5 2:HELLOWORLD --from mykey
 	}
 }
 
+// GetCmdCancelRequest implements the cancel request command handler.
+func GetCmdCancelRequest(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "cancel-request [request-id]",
+		Short: "Cancel an open request that has not received sufficient reports",
+		Args:  cobra.ExactArgs(1),
+		Long: strings.TrimSpace(
+			fmt.Sprintf(`Cancel an open request that has not received sufficient reports yet. Only the requester can
+cancel a request, and its priority fee is refunded.
+Example:
+$ %s tx zoracle cancel-request 1 --from mykey
+`,
+				version.ClientName,
+			),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
+			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(authclient.GetTxEncoder(cdc))
+
+			int64RequestID, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgCancelRequest(types.RequestID(int64RequestID), cliCtx.GetFromAddress())
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			return authclient.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
 // GetCmdCreateDataSource implements the create data source command handler.
 func GetCmdCreateDataSource(cdc *codec.Codec) *cobra.Command {
 	cmd := &cobra.Command{
@@ -3,6 +3,7 @@ package rest
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/cosmos/cosmos-sdk/client/context"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -10,6 +11,7 @@ import (
 	authclient "github.com/cosmos/cosmos-sdk/x/auth/client"
 	"github.com/gorilla/mux"
 
+	"github.com/cosmos/gaia/x/zoracle/client/utils"
 	"github.com/cosmos/gaia/x/zoracle/internal/types"
 )
 
@@ -35,12 +37,20 @@ func buildRequestRESTInfo(
 	request.SufficientValidatorCount = queryRequest.Request.SufficientValidatorCount
 	request.ExpirationHeight = queryRequest.Request.ExpirationHeight
 	request.ResolveStatus = queryRequest.Request.ResolveStatus
+	request.FailureReason = queryRequest.Request.FailureReason
+	request.PriorityFee = queryRequest.Request.PriorityFee
+	request.ResultHash = queryRequest.Request.ResultHash
+	request.Pruned = queryRequest.Request.Pruned
+	request.Expired = queryRequest.Request.Expired
+	request.Requester = queryRequest.Request.Requester
 	request.RawDataRequests = queryRequest.RawDataRequests
 
 	request.Result = queryRequest.Result
+	request.ReportCommitment = queryRequest.ReportCommitment
 
 	if withRequestTx {
-		// Get request detail
+		// Get request detail. The tx index is optional, so the request is still returned without
+		// its tx detail if the node does not index txs.
 		searchRequest, err := authclient.QueryTxsByEvents(
 			ctx,
 			[]string{fmt.Sprintf("%s.%s='%d'",
@@ -49,15 +59,15 @@ func buildRequestRESTInfo(
 			1,
 			"",
 		)
-		if err != nil || len(searchRequest.Txs) != 1 {
-			return RequestRESTInfo{}, err
-		}
-		request.RequestTx = buildTxDetail(&searchRequest.Txs[0])
-		for _, msg := range searchRequest.Txs[0].Tx.GetMsgs() {
-			msgRequest, ok := msg.(types.MsgRequestData)
-			if ok {
-				request.Requester = msgRequest.Sender
-				break
+		if err == nil && len(searchRequest.Txs) == 1 {
+			request.RequestTx = buildTxDetail(&searchRequest.Txs[0])
+			// Requests made before the requester was stored only have it in their tx.
+			for _, msg := range searchRequest.Txs[0].Tx.GetMsgs() {
+				msgRequest, ok := msg.(types.MsgRequestData)
+				if ok && request.Requester.Empty() {
+					request.Requester = msgRequest.Sender
+					break
+				}
 			}
 		}
 	}
@@ -225,6 +235,28 @@ func getRequestByIDHandler(cliCtx context.CLIContext, storeName string) http.Han
 	}
 }
 
+// getResultHandler returns the result of a request, with the IAVL proof of its store entry when
+// the prove query parameter is true.
+func getResultHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		requestID := vars[requestIDTag]
+		prove := r.URL.Query().Get("prove") == "true"
+
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		result, err := utils.QueryResult(cliCtx, storeName, requestID, prove)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		rest.PostProcessResponse(w, cliCtx, result)
+	}
+}
+
 func getRequestsHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		_, page, limit, err := rest.ParseHTTPArgsWithLimit(r, 100)
@@ -273,13 +305,11 @@ func getRequestsHandler(cliCtx context.CLIContext, storeName string) http.Handle
 
 		for idx := len(queryRequests) - 1; idx >= 0; idx-- {
 			request, err := buildRequestRESTInfo(cliCtx, queryRequests[idx], true, true)
-			if err == nil {
-				requests = append(requests, request)
+			if err != nil {
+				rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+				return
 			}
-		}
-		if err != nil {
-			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
-			return
+			requests = append(requests, request)
 		}
 
 		rest.PostProcessResponse(w, cliCtx, requests)
@@ -303,3 +333,273 @@ func getRequestNumberHandler(cliCtx context.CLIContext, storeName string) http.H
 		rest.PostProcessResponse(w, cliCtx, requestNumber)
 	}
 }
+
+func getParamsHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, types.QueryParams), nil)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		var params types.Params
+		err = cliCtx.Codec.UnmarshalJSON(res, &params)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		rest.PostProcessResponse(w, cliCtx, params)
+	}
+}
+
+// getLatestResultHandler returns the latest result of an oracle script for the hex encoded
+// calldata given in the calldata query parameter.
+func getLatestResultHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		oracleScriptID := vars[oracleScriptIDTag]
+		calldata := r.URL.Query().Get("calldata")
+		res, _, err := cliCtx.QueryWithData(
+			fmt.Sprintf("custom/%s/%s/%s/%s", storeName, types.QueryLatestResult, oracleScriptID, calldata), nil,
+		)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var latestResult types.LatestResultQuerierInfo
+		err = cliCtx.Codec.UnmarshalJSON(res, &latestResult)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		rest.PostProcessResponse(w, cliCtx, latestResult)
+	}
+}
+
+// getReferenceDataHandler returns the latest rate of the base currency in terms of the quote currency.
+func getReferenceDataHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		res, _, err := cliCtx.QueryWithData(
+			fmt.Sprintf("custom/%s/%s/%s/%s", storeName, types.QueryReferenceData, vars[baseTag], vars[quoteTag]), nil,
+		)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var referenceData types.ReferenceData
+		err = cliCtx.Codec.UnmarshalJSON(res, &referenceData)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		rest.PostProcessResponse(w, cliCtx, referenceData)
+	}
+}
+
+func getSubscriptionByIDHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		res, _, err := cliCtx.QueryWithData(
+			fmt.Sprintf("custom/%s/%s/%s", storeName, types.QuerySubscriptionByID, vars[subscriptionIDTag]), nil,
+		)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var subscription types.Subscription
+		err = cliCtx.Codec.UnmarshalJSON(res, &subscription)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		rest.PostProcessResponse(w, cliCtx, subscription)
+	}
+}
+
+// getRequestsByRequesterHandler returns a page of the requests made by an address, starting from
+// the start_id query parameter. Requests made before the requester was recorded on requests are
+// missing from the requester index, so they are never returned.
+func getRequestsByRequesterHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		_, _, limit, err := rest.ParseHTTPArgsWithLimit(r, 100)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		startID := r.FormValue("start_id")
+		if startID == "" {
+			startID = "1"
+		}
+
+		var queryRequests []types.RequestQuerierInfo
+		res, _, err := cliCtx.QueryWithData(
+			fmt.Sprintf(
+				"custom/%s/%s/%s/%s/%d",
+				storeName, types.QueryRequestsByRequester, vars[addressTag], startID, limit,
+			),
+			nil,
+		)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		err = cliCtx.Codec.UnmarshalJSON(res, &queryRequests)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		requests := make([]RequestRESTInfo, 0)
+		for _, queryRequest := range queryRequests {
+			request, err := buildRequestRESTInfo(cliCtx, queryRequest, false, false)
+			if err != nil {
+				rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			requests = append(requests, request)
+		}
+		rest.PostProcessResponse(w, cliCtx, requests)
+	}
+}
+
+// parseInt64Param parses the given query string parameter of the request, which is zero if absent.
+func parseInt64Param(r *http.Request, name string) (int64, error) {
+	raw := r.FormValue(name)
+	if raw == "" {
+		return 0, nil
+	}
+	value, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("wrong format for %s: %s", name, err.Error())
+	}
+	return value, nil
+}
+
+// parseRequestSearchParams builds the search parameters from the query string of the request.
+func parseRequestSearchParams(r *http.Request) (types.QueryRequestsParams, error) {
+	_, _, limit, err := rest.ParseHTTPArgsWithLimit(r, 100)
+	if err != nil {
+		return types.QueryRequestsParams{}, err
+	}
+	startID, err := parseInt64Param(r, "start_id")
+	if err != nil {
+		return types.QueryRequestsParams{}, err
+	}
+	oracleScriptID, err := parseInt64Param(r, "oracle_script_id")
+	if err != nil {
+		return types.QueryRequestsParams{}, err
+	}
+	minHeight, err := parseInt64Param(r, "min_height")
+	if err != nil {
+		return types.QueryRequestsParams{}, err
+	}
+	maxHeight, err := parseInt64Param(r, "max_height")
+	if err != nil {
+		return types.QueryRequestsParams{}, err
+	}
+
+	var requester sdk.AccAddress
+	if raw := r.FormValue("requester"); raw != "" {
+		requester, err = sdk.AccAddressFromBech32(raw)
+		if err != nil {
+			return types.QueryRequestsParams{}, err
+		}
+	}
+	var validator sdk.ValAddress
+	if raw := r.FormValue("validator"); raw != "" {
+		validator, err = sdk.ValAddressFromBech32(raw)
+		if err != nil {
+			return types.QueryRequestsParams{}, err
+		}
+	}
+
+	params := types.NewQueryRequestsParams(
+		types.RequestID(startID), limit, types.OracleScriptID(oracleScriptID), requester,
+		r.FormValue("status"), validator, minHeight, maxHeight,
+		r.FormValue("source_port"), r.FormValue("source_channel"),
+	)
+	return params, params.Validate()
+}
+
+func searchRequestsHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params, err := parseRequestSearchParams(r)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		bz, err := cliCtx.Codec.MarshalJSON(params)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, types.QuerySearchRequests), bz)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var searchResult types.RequestSearchResult
+		err = cliCtx.Codec.UnmarshalJSON(res, &searchResult)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		requests := make([]RequestRESTInfo, 0, len(searchResult.Requests))
+		for _, queryRequest := range searchResult.Requests {
+			request, err := buildRequestRESTInfo(cliCtx, queryRequest, true, false)
+			if err != nil {
+				rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			requests = append(requests, request)
+		}
+		rest.PostProcessResponse(w, cliCtx, RequestSearchRESTResult{Requests: requests, NextID: searchResult.NextID})
+	}
+}
+
+func getPendingAssignmentsHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		_, _, limit, err := rest.ParseHTTPArgsWithLimit(r, 100)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		startID := r.FormValue("start_id")
+		if startID == "" {
+			startID = "1"
+		}
+
+		res, _, err := cliCtx.QueryWithData(
+			fmt.Sprintf(
+				"custom/%s/%s/%s/%s/%d",
+				storeName, types.QueryPendingAssignments, vars[validatorTag], startID, limit,
+			),
+			nil,
+		)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var assignments []types.PendingAssignmentQuerierInfo
+		err = cliCtx.Codec.UnmarshalJSON(res, &assignments)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		rest.PostProcessResponse(w, cliCtx, assignments)
+	}
+}
@@ -12,6 +12,11 @@ const (
 	requestIDTag      = "requestIDTag"
 	dataSourceIDTag   = "dataSourceIDTag"
 	oracleScriptIDTag = "oracleScriptIDTag"
+	baseTag           = "baseTag"
+	quoteTag          = "quoteTag"
+	subscriptionIDTag = "subscriptionIDTag"
+	addressTag        = "addressTag"
+	validatorTag      = "validatorTag"
 )
 
 func RegisterRoutes(cliCtx context.CLIContext, r *mux.Router, storeName string) {
@@ -20,6 +25,14 @@ func RegisterRoutes(cliCtx context.CLIContext, r *mux.Router, storeName string)
 	r.HandleFunc(fmt.Sprintf("/%s/oracle_script/{%s}", storeName, oracleScriptIDTag), getOracleScriptByIDHandler(cliCtx, storeName)).Methods("GET")
 	r.HandleFunc(fmt.Sprintf("/%s/oracle_scripts", storeName), getOracleScriptsHandler(cliCtx, storeName)).Methods("GET")
 	r.HandleFunc(fmt.Sprintf("/%s/request/{%s}", storeName, requestIDTag), getRequestByIDHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/result/{%s}", storeName, requestIDTag), getResultHandler(cliCtx, storeName)).Methods("GET")
 	r.HandleFunc(fmt.Sprintf("/%s/requests", storeName), getRequestsHandler(cliCtx, storeName)).Methods("GET")
 	r.HandleFunc(fmt.Sprintf("/%s/request_number", storeName), getRequestNumberHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/params", storeName), getParamsHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/latest_result/{%s}", storeName, oracleScriptIDTag), getLatestResultHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/reference_data/{%s}/{%s}", storeName, baseTag, quoteTag), getReferenceDataHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/subscription/{%s}", storeName, subscriptionIDTag), getSubscriptionByIDHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/requests_by_requester/{%s}", storeName, addressTag), getRequestsByRequesterHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/search_requests", storeName), searchRequestsHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/pending_assignments/{%s}", storeName, validatorTag), getPendingAssignmentsHandler(cliCtx, storeName)).Methods("GET")
 }
@@ -20,11 +20,17 @@ type RequestRESTInfo struct {
 	SufficientValidatorCount int64                                `json:"sufficientValidatorCount"`
 	ExpirationHeight         int64                                `json:"expirationHeight"`
 	ResolveStatus            types.ResolveStatus                  `json:"resolveStatus"`
+	FailureReason            types.FailureReason                  `json:"failureReason"`
+	PriorityFee              sdk.Coins                            `json:"priorityFee"`
+	ResultHash               []byte                               `json:"resultHash"`
+	Pruned                   bool                                 `json:"pruned"`
+	Expired                  bool                                 `json:"expired"`
 	Requester                sdk.AccAddress                       `json:"requester"`
 	RequestTx                TxDetail                             `json:"requestTx,omitempty"`
 	RawDataRequests          []types.RawDataRequestWithExternalID `json:"rawDataRequests"`
 	Reports                  []ReportDetail                       `json:"reports"`
 	Result                   types.Result                         `json:"result"`
+	ReportCommitment         *types.ReportCommitment              `json:"reportCommitment,omitempty"`
 }
 
 type TxDetail struct {
@@ -32,3 +38,8 @@ type TxDetail struct {
 	Height    int64  `json:"height"`
 	Timestamp string `json:"timestamp"`
 }
+
+type RequestSearchRESTResult struct {
+	Requests []RequestRESTInfo `json:"requests"`
+	NextID   types.RequestID   `json:"nextID"`
+}
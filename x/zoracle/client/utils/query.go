@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+	"github.com/cosmos/gaia/x/zoracle/proof"
+)
+
+// QueryResult returns the result of the given request. If prove is set, the result is read from
+// the zoracle store together with its IAVL proof, which proof.VerifyResult can check against the
+// app hash of the next block.
+func QueryResult(
+	cliCtx context.CLIContext, storeName string, requestID string, prove bool,
+) (proof.ResultWithProof, error) {
+	res, height, err := cliCtx.QueryWithData(
+		fmt.Sprintf("custom/%s/%s/%s", storeName, types.QueryRequestByID, requestID), nil,
+	)
+	if err != nil {
+		return proof.ResultWithProof{}, err
+	}
+
+	var queryRequest types.RequestQuerierInfo
+	if err := cliCtx.Codec.UnmarshalJSON(res, &queryRequest); err != nil {
+		return proof.ResultWithProof{}, err
+	}
+	if queryRequest.Request.ResolveStatus != types.Success {
+		return proof.ResultWithProof{}, fmt.Errorf("request %s has no result", requestID)
+	}
+
+	resultWithProof := proof.ResultWithProof{
+		RequestID:      queryRequest.ID,
+		OracleScriptID: queryRequest.Request.OracleScriptID,
+		Calldata:       queryRequest.Request.Calldata,
+		Result:         queryRequest.Result,
+		Height:         height,
+	}
+	if !prove {
+		return resultWithProof, nil
+	}
+
+	// Query the store at the same height, so the proof covers the request read above.
+	resp, err := cliCtx.WithHeight(height).QueryABCI(abci.RequestQuery{
+		Path: fmt.Sprintf("/store/%s/key", storeName),
+		Data: types.ResultStoreKey(
+			resultWithProof.RequestID, resultWithProof.OracleScriptID, resultWithProof.Calldata,
+		),
+		Prove: true,
+	})
+	if err != nil {
+		return proof.ResultWithProof{}, err
+	}
+	if resp.Value == nil {
+		return proof.ResultWithProof{}, fmt.Errorf("result of request %s not found in store", requestID)
+	}
+	result, err := types.DecodeResult(resp.Value)
+	if err != nil {
+		return proof.ResultWithProof{}, fmt.Errorf("invalid result of request %s in store: %v", requestID, err)
+	}
+	resultWithProof.Result = result
+	resultWithProof.Height = resp.Height
+	resultWithProof.Proof = resp.Proof
+	return resultWithProof, nil
+}
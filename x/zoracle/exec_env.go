@@ -3,10 +3,23 @@ package zoracle
 import (
 	"errors"
 
+	"github.com/cosmos/gaia/owasm"
 	"github.com/cosmos/gaia/x/zoracle/internal/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
+// GetVMLimits returns the Owasm virtual machine resource limits from the current parameters.
+func GetVMLimits(ctx sdk.Context, keeper Keeper) owasm.Limits {
+	return owasm.Limits{
+		MaxMemoryPages:     keeper.MaxMemoryPages(ctx),
+		MaxTableSize:       keeper.MaxTableSize(ctx),
+		MaxValueSlots:      keeper.MaxValueSlots(ctx),
+		MaxCallStackDepth:  keeper.MaxCallStackDepth(ctx),
+		DefaultMemoryPages: keeper.DefaultMemoryPages(ctx),
+		DefaultTableSize:   keeper.DefaultTableSize(ctx),
+	}
+}
+
 type ExecutionEnvironment struct {
 	ctx       sdk.Context
 	keeper    Keeper
@@ -98,3 +111,11 @@ func (env *ExecutionEnvironment) GetExternalData(
 	}
 	return rawReport.Data, rawReport.ExitCode, nil
 }
+
+func (env *ExecutionEnvironment) GetLatestResult(oracleScriptID int64, calldata []byte) ([]byte, error) {
+	result, err := env.keeper.GetLatestResult(env.ctx, types.OracleScriptID(oracleScriptID), calldata)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
@@ -1,6 +1,8 @@
 package zoracle
 
 import (
+	"fmt"
+
 	"github.com/cosmos/gaia/x/zoracle/internal/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	abci "github.com/tendermint/tendermint/abci/types"
@@ -25,7 +27,31 @@ func NewGenesisState(
 }
 
 func ValidateGenesis(data GenesisState) error {
-	return nil
+	if data.Params.PendingAgingBlocks <= 0 || data.Params.MaxPendingWaitBlocks <= 0 {
+		return fmt.Errorf("pending aging and wait blocks must be positive")
+	}
+	if data.Params.ResultRetentionBlocks < 0 || data.Params.ResultRetentionRequests < 0 {
+		return fmt.Errorf("result retention windows must not be negative")
+	}
+	if data.Params.PruneGasLimit == 0 {
+		return fmt.Errorf("prune gas limit must be positive")
+	}
+	if data.Params.SubscriptionGasPrice == 0 {
+		return fmt.Errorf("subscription gas price must be positive")
+	}
+	if data.Params.MaxSubscriptionsPerBlock <= 0 || data.Params.MaxSubscriptionDuration <= 0 {
+		return fmt.Errorf("subscription limits must be positive")
+	}
+	if err := data.Params.VMLimits().Validate(); err != nil {
+		return err
+	}
+	if err := data.Params.GasSchedule.Validate(); err != nil {
+		return err
+	}
+	if err := types.ValidateReportCleanupMode(data.Params.ReportCleanupMode); err != nil {
+		return err
+	}
+	return data.Params.ReferenceSymbols.Validate()
 }
 
 // DefaultGenesisState returns the default genesis state.
@@ -48,6 +74,25 @@ func InitGenesis(ctx sdk.Context, k Keeper, data GenesisState) []abci.ValidatorU
 	k.SetMaxNameLength(ctx, data.Params.MaxNameLength)
 	k.SetMaxDescriptionLength(ctx, data.Params.MaxDescriptionLength)
 	k.SetGasPerRawDataRequestPerValidator(ctx, data.Params.GasPerRawDataRequestPerValidator)
+	k.SetMaxMemoryPages(ctx, data.Params.MaxMemoryPages)
+	k.SetMaxTableSize(ctx, data.Params.MaxTableSize)
+	k.SetMaxValueSlots(ctx, data.Params.MaxValueSlots)
+	k.SetMaxCallStackDepth(ctx, data.Params.MaxCallStackDepth)
+	k.SetDefaultMemoryPages(ctx, data.Params.DefaultMemoryPages)
+	k.SetDefaultTableSize(ctx, data.Params.DefaultTableSize)
+	k.SetGasSchedule(ctx, data.Params.GasSchedule)
+	k.SetPendingAgingBlocks(ctx, data.Params.PendingAgingBlocks)
+	k.SetMaxPendingWaitBlocks(ctx, data.Params.MaxPendingWaitBlocks)
+	k.SetResultRetentionBlocks(ctx, data.Params.ResultRetentionBlocks)
+	k.SetResultRetentionRequests(ctx, data.Params.ResultRetentionRequests)
+	k.SetPruneGasLimit(ctx, data.Params.PruneGasLimit)
+	k.SetReportCleanupMode(ctx, data.Params.ReportCleanupMode)
+	k.SetReferenceSymbols(ctx, data.Params.ReferenceSymbols)
+	k.SetSubscriptionGasPrice(ctx, data.Params.SubscriptionGasPrice)
+	k.SetMaxSubscriptionsPerBlock(ctx, data.Params.MaxSubscriptionsPerBlock)
+	k.SetMaxSubscriptionDuration(ctx, data.Params.MaxSubscriptionDuration)
+	// Make sure the reference data account exists as a module account before anyone funds it.
+	k.SupplyKeeper.GetModuleAccount(ctx, types.ReferenceDataAccountName)
 
 	for _, dataSource := range data.DataSources {
 		_, err := k.AddDataSource(
@@ -1,8 +1,11 @@
 package zoracle
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"math"
+	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
@@ -33,13 +36,24 @@ func NewHandler(keeper Keeper) sdk.Handler {
 			return handleMsgAddOracleAddress(ctx, keeper, msg)
 		case MsgRemoveOracleAdderess:
 			return handleMsgRemoveOracleAddress(ctx, keeper, msg)
+		case MsgSubscribe:
+			return handleMsgSubscribe(ctx, keeper, msg)
+		case MsgCancelRequest:
+			return handleMsgCancelRequest(ctx, keeper, msg)
 		case channeltypes.MsgPacket:
 			switch data := msg.Data.(type) {
 			case OraclePacketData:
-				fmt.Println("RECEIVE PACKET!!!", data)
+				keeper.Logger(ctx).Info(
+					"received oracle packet",
+					"port", msg.DestinationPort, "channel", msg.DestinationChannel, "sequence", msg.Sequence,
+				)
 				return nil, nil
 			default:
-				fmt.Println("unrecognized oracle packet data", data)
+				keeper.Logger(ctx).Error(
+					"unrecognized oracle packet data",
+					"port", msg.DestinationPort, "channel", msg.DestinationChannel, "sequence", msg.Sequence,
+					"type", fmt.Sprintf("%T", data),
+				)
 				return nil, sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "unrecognized oracle packet data type: %T", data)
 			}
 		default:
@@ -171,114 +185,308 @@ func addUint64Overflow(a, b uint64) (uint64, bool) {
 	return a + b, false
 }
 
-func handleEndBlock(ctx sdk.Context, keeper Keeper) sdk.Result {
-	pendingList := keeper.GetPendingResolveList(ctx)
+// resolveRequest sets the final status of the request and emits an event about it.
+func resolveRequest(
+	ctx sdk.Context, keeper Keeper, requestID RequestID,
+	status types.ResolveStatus, reason types.FailureReason, gasUsed uint64,
+) {
+	keeper.SetResolve(ctx, requestID, status, reason)
+	if reason == types.FailureReasonPendingTimeout {
+		refundPriorityFee(ctx, keeper, requestID)
+	} else {
+		payPriorityFee(ctx, keeper, requestID)
+	}
+	keeper.CleanupReports(ctx, requestID)
+	keeper.Logger(ctx).Debug("request failed", "request_id", requestID, "reason", reason.String(), "gas_used", gasUsed)
+	emitRequestResolved(ctx, requestID, status, reason, gasUsed, nil, 0)
+}
+
+// payPriorityFee pays the priority fee of a resolved request to the validators. The fee is
+// paid whether or not the request succeeded, since validators did the work either way.
+func payPriorityFee(ctx sdk.Context, keeper Keeper, requestID RequestID) {
+	if err := keeper.PayPriorityFee(ctx, requestID); err != nil { // should never happen
+		keeper.Logger(ctx).Error("failed to pay priority fee", "request_id", requestID, "err", err)
+	}
+}
+
+// refundPriorityFee returns the priority fee of a request that timed out while pending to its
+// requester, since the validators never executed it.
+func refundPriorityFee(ctx sdk.Context, keeper Keeper, requestID RequestID) {
+	if err := keeper.RefundPriorityFee(ctx, requestID); err != nil { // should never happen
+		keeper.Logger(ctx).Error("failed to refund priority fee", "request_id", requestID, "err", err)
+	}
+}
+
+// emitRequestResolved emits an event describing how the request was resolved. The result hash
+// is empty unless a result was produced, and the packet sequence is zero unless the result was
+// sent out over IBC.
+func emitRequestResolved(
+	ctx sdk.Context, requestID RequestID, status types.ResolveStatus, reason types.FailureReason,
+	gasUsed uint64, result []byte, packetSequence uint64,
+) {
+	if !ctx.IsCheckTx() {
+		moduleMetrics.RequestsResolved.With("status", resolveStatusLabel(status)).Add(1)
+	}
+
+	resultHash := ""
+	if result != nil {
+		hash := sha256.Sum256(result)
+		resultHash = hex.EncodeToString(hash[:])
+	}
+	ctx.EventManager().EmitEvent(sdk.NewEvent(
+		types.EventTypeRequestResolved,
+		sdk.NewAttribute(types.AttributeKeyID, fmt.Sprintf("%d", requestID)),
+		sdk.NewAttribute(types.AttributeKeyResolveStatus, fmt.Sprintf("%d", status)),
+		sdk.NewAttribute(types.AttributeKeyFailureReason, reason.String()),
+		sdk.NewAttribute(types.AttributeKeyGasUsed, fmt.Sprintf("%d", gasUsed)),
+		sdk.NewAttribute(types.AttributeKeyResultHash, resultHash),
+		sdk.NewAttribute(types.AttributeKeyPacketSequence, fmt.Sprintf("%d", packetSequence)),
+	))
+}
+
+// resolveStatusLabel returns the metrics label of the given resolve status.
+func resolveStatusLabel(status types.ResolveStatus) string {
+	switch status {
+	case types.Success:
+		return "success"
+	case types.Failure:
+		return "failure"
+	case types.Cancelled:
+		return "cancelled"
+	default:
+		return "open"
+	}
+}
+
+// failureReasonOf returns the failure reason for an error returned by Owasm execution.
+func failureReasonOf(errOwasm error) types.FailureReason {
+	if errOwasm == owasm.ErrOutOfGas {
+		return types.FailureReasonOutOfGas
+	}
+	return types.FailureReasonExecutionError
+}
+
+// sendResultPacket sends the result of the request back to the chain that made it, returning
+// the sequence of the IBC packet or zero if the packet could not be sent.
+func sendResultPacket(
+	ctx sdk.Context, keeper Keeper, requestID RequestID, request types.Request, result []byte,
+) uint64 {
+	// Requests made on this chain, such as reference data requests, have nowhere to send the result.
+	if request.SourcePort == "" && request.SourceChannel == "" {
+		return 0
+	}
+
+	logger := keeper.Logger(ctx).With(
+		"request_id", requestID, "port", request.SourcePort, "channel", request.SourceChannel,
+	)
+	sourceChannelEnd, found := keeper.ChannelKeeper.GetChannel(ctx, request.SourcePort, request.SourceChannel)
+	if !found {
+		logger.Error("source channel not found")
+		return 0
+	}
+
+	destinationPort := sourceChannelEnd.Counterparty.PortID
+	destinationChannel := sourceChannelEnd.Counterparty.ChannelID
+
+	// get the next sequence
+	sequence, found := keeper.ChannelKeeper.GetNextSequenceSend(ctx, request.SourcePort, request.SourceChannel)
+	if !found {
+		logger.Error("next send sequence not found")
+		return 0
+	}
+
+	packetData := NewOraclePacketData(
+		result,
+	)
+
+	packet := channel.NewPacket(
+		packetData,
+		sequence,
+		request.SourcePort, request.SourceChannel,
+		destinationPort, destinationChannel,
+	)
+
+	err := keeper.ChannelKeeper.SendPacket(ctx, packet)
+	if err != nil {
+		logger.Error("failed to send result packet", "sequence", sequence, "err", err)
+		return 0
+	}
+	logger.Info("sent result packet", "sequence", sequence)
+	return sequence
+}
+
+// resolvePendingRequest executes the given pending request and resolves it with the result,
+// returning the amount of execute gas used.
+func resolvePendingRequest(
+	ctx sdk.Context, keeper Keeper, requestID RequestID, request types.Request, vmLimits owasm.Limits,
+) uint64 {
+	env, err := NewExecutionEnvironment(ctx, keeper, requestID)
+	if err != nil { // should never happen
+		resolveRequest(ctx, keeper, requestID, types.Failure, types.FailureReasonBadEnvironment, 0)
+		return 0
+	}
+
+	script, err := keeper.GetOracleScript(ctx, request.OracleScriptID)
+	if err != nil { // should never happen
+		resolveRequest(ctx, keeper, requestID, types.Failure, types.FailureReasonOracleScriptNotFound, 0)
+		return 0
+	}
+
+	gasSchedule, err := keeper.GetGasScheduleByVersion(ctx, request.GasScheduleVersion)
+	if err != nil { // should never happen
+		resolveRequest(ctx, keeper, requestID, types.Failure, types.FailureReasonGasScheduleNotFound, 0)
+		return 0
+	}
+
+	executeStart := time.Now()
+	result, gasUsed, errOwasm := owasm.Execute(
+		&env, script.Code, "execute", request.Calldata, request.ExecuteGas, vmLimits, gasSchedule,
+	)
+	moduleMetrics.OwasmExecutionDuration.With("entry", "execute").Observe(time.Since(executeStart).Seconds())
+
+	if gasUsed > request.ExecuteGas {
+		gasUsed = request.ExecuteGas
+	}
+
+	if errOwasm != nil {
+		resolveRequest(ctx, keeper, requestID, types.Failure, failureReasonOf(errOwasm), gasUsed)
+		return gasUsed
+	}
+
+	errResult := keeper.AddResult(ctx, requestID, request.OracleScriptID, request.Calldata, result)
+	if errResult != nil {
+		resolveRequest(ctx, keeper, requestID, types.Failure, types.FailureReasonBadResult, gasUsed)
+		return gasUsed
+	}
+
+	keeper.SetResolve(ctx, requestID, types.Success, types.FailureReasonNone)
+	payPriorityFee(ctx, keeper, requestID)
+	keeper.CleanupReports(ctx, requestID)
+
+	// Send IBC Packet out!
+	sequence := sendResultPacket(ctx, keeper, requestID, request, result)
+	emitRequestResolved(ctx, requestID, types.Success, types.FailureReasonNone, gasUsed, result, sequence)
+	return gasUsed
+}
+
+// handleEndBlock resolves as many pending requests as fit in EndBlockExecuteGasLimit, in the
+// order given by GetPendingRequestsInScheduleOrder. Requests that do not fit the remaining gas
+// are skipped, except that nothing is served past an aged request that does not fit, so large
+// requests always make progress. Requests pending for more than MaxPendingWaitBlocks fail. It then
+// prunes the data of old requests.
+func handleEndBlock(ctx sdk.Context, keeper Keeper) {
 	endBlockExecuteGasLimit := keeper.EndBlockExecuteGasLimit(ctx)
+	agingBlocks := keeper.PendingAgingBlocks(ctx)
+	maxWaitBlocks := keeper.MaxPendingWaitBlocks(ctx)
+	vmLimits := GetVMLimits(ctx, keeper)
 	gasConsumed := uint64(0)
-	firstUnresolvedRequestIndex := len(pendingList)
 
-	for i, requestID := range pendingList {
+	scheduled := keeper.GetPendingRequestsInScheduleOrder(ctx)
+	resolvedCount := 0
+	dequeue := func(requestID RequestID) {
+		keeper.RemovePendingRequest(ctx, requestID)
+		resolvedCount++
+	}
+	blocked := false
+	for _, requestID := range scheduled {
 		request, err := keeper.GetRequest(ctx, requestID)
 		if err != nil { // should never happen
-			keeper.SetResolve(ctx, requestID, types.Failure)
+			resolveRequest(ctx, keeper, requestID, types.Failure, types.FailureReasonBadEnvironment, 0)
+			dequeue(requestID)
 			continue
 		}
 
 		// Discard the request if execute gas is greater than EndBlockExecuteGasLimit.
 		if request.ExecuteGas > endBlockExecuteGasLimit {
-			keeper.SetResolve(ctx, requestID, types.Failure)
+			resolveRequest(ctx, keeper, requestID, types.Failure, types.FailureReasonExecuteGasOverLimit, 0)
+			dequeue(requestID)
 			continue
 		}
 
-		estimatedGasConsumed, overflow := addUint64Overflow(gasConsumed, request.ExecuteGas)
-		if overflow || estimatedGasConsumed > endBlockExecuteGasLimit {
-			firstUnresolvedRequestIndex = i
-			break
-		}
-
-		env, err := NewExecutionEnvironment(ctx, keeper, requestID)
-		if err != nil { // should never happen
-			keeper.SetResolve(ctx, requestID, types.Failure)
-			continue
-		}
-
-		script, err := keeper.GetOracleScript(ctx, request.OracleScriptID)
-		if err != nil { // should never happen
-			keeper.SetResolve(ctx, requestID, types.Failure)
+		waited := request.PendingBlocks(ctx.BlockHeight())
+		if waited > maxWaitBlocks {
+			resolveRequest(ctx, keeper, requestID, types.Failure, types.FailureReasonPendingTimeout, 0)
+			dequeue(requestID)
 			continue
 		}
 
-		result, gasUsed, errOwasm := owasm.Execute(
-			&env, script.Code, "execute", request.Calldata, request.ExecuteGas,
-		)
-
-		if gasUsed > request.ExecuteGas {
-			gasUsed = request.ExecuteGas
-		}
-
-		gasConsumed, overflow = addUint64Overflow(gasConsumed, gasUsed)
-		// Must never overflow because we already checked for overflow above with
-		// gasConsumed + request.ExecuteGas (which is >= gasUsed).
-		if overflow {
-			// TODO: FIX THIS
-			panic("GAS OVERFLOW")
-		}
-
-		if errOwasm != nil {
-			keeper.SetResolve(ctx, requestID, types.Failure)
+		if blocked {
 			continue
 		}
-
-		errResult := keeper.AddResult(ctx, requestID, request.OracleScriptID, request.Calldata, result)
-		if errResult != nil {
-			keeper.SetResolve(ctx, requestID, types.Failure)
+		estimatedGasConsumed, overflow := addUint64Overflow(gasConsumed, request.ExecuteGas)
+		if overflow { // should never happen, since both are within EndBlockExecuteGasLimit
+			resolveRequest(ctx, keeper, requestID, types.Failure, types.FailureReasonExecuteGasOverLimit, 0)
+			dequeue(requestID)
 			continue
 		}
-
-		keeper.SetResolve(ctx, requestID, types.Success)
-
-		// Send IBC Packet out!
-		sourceChannelEnd, found := keeper.ChannelKeeper.GetChannel(ctx, request.SourcePort, request.SourceChannel)
-		if !found {
-			fmt.Println("SOURCE NOT FOUND", request.SourcePort, request.SourceChannel)
+		if estimatedGasConsumed > endBlockExecuteGasLimit {
+			if waited >= agingBlocks {
+				blocked = true
+			}
 			continue
 		}
+		// The gas used is at most the execute gas of the request, so the sum stays within the
+		// estimate and cannot overflow.
+		gasConsumed += resolvePendingRequest(ctx, keeper, requestID, request, vmLimits)
+		dequeue(requestID)
+	}
 
-		destinationPort := sourceChannelEnd.Counterparty.PortID
-		destinationChannel := sourceChannelEnd.Counterparty.ChannelID
+	for _, requestID := range keeper.ExpireRequests(ctx) {
+		keeper.CleanupReports(ctx, requestID)
+		emitRequestResolved(ctx, requestID, types.Failure, types.FailureReasonExpired, 0, nil, 0)
+	}
+	keeper.PruneRequests(ctx)
+	// Governance runs its EndBlock first, so requests from the next block on are made under a gas
+	// schedule that a proposal set in this block.
+	keeper.RecordModifiedGasSchedule(ctx)
+
+	moduleMetrics.EndBlockGasConsumed.Set(float64(gasConsumed))
+	moduleMetrics.EndBlockGasLimit.Set(float64(endBlockExecuteGasLimit))
+	moduleMetrics.PendingRequests.Set(float64(len(scheduled) - resolvedCount))
+}
 
-		// get the next sequence
-		sequence, found := keeper.ChannelKeeper.GetNextSequenceSend(ctx, request.SourcePort, request.SourceChannel)
-		if !found {
-			fmt.Println("SEQUENCE NOT FOUND", request.SourcePort, request.SourceChannel)
-			continue
-		}
+func handleMsgRequestData(
+	ctx sdk.Context, keeper Keeper, msg MsgRequestData,
+) (*sdk.Result, error) {
 
-		packetData := NewOraclePacketData(
-			result,
-		)
+	id, err := submitRequest(ctx, keeper, msg)
+	if err != nil {
+		return nil, err
+	}
 
-		packet := channel.NewPacket(
-			packetData,
-			sequence,
-			request.SourcePort, request.SourceChannel,
-			destinationPort, destinationChannel,
-		)
+	emitRequest(ctx, id)
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
 
-		err = keeper.ChannelKeeper.SendPacket(ctx, packet)
-		fmt.Println("SEND PACKET:", err)
+// submitRequest adds the request described by msg, runs the prepare phase of its oracle script,
+// and charges the data source fees and the priority fee to the sender of msg.
+func submitRequest(ctx sdk.Context, keeper Keeper, msg MsgRequestData) (RequestID, error) {
+	id, err := prepareRequest(ctx, keeper, msg)
+	if err != nil {
+		return 0, err
 	}
 
-	keeper.SetPendingResolveList(ctx, pendingList[firstUnresolvedRequestIndex:])
+	err = keeper.PayDataSourceFees(ctx, id, msg.Sender)
+	if err != nil {
+		return 0, err
+	}
 
-	// TODO: Emit event
-	return sdk.Result{Events: ctx.EventManager().Events()}
+	err = keeper.EscrowPriorityFee(ctx, msg.Sender, msg.PriorityFee)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
 }
 
-func handleMsgRequestData(
-	ctx sdk.Context, keeper Keeper, msg MsgRequestData,
-) (*sdk.Result, error) {
+// The size of the prepare trace returned in the error of a failed request in CheckTx.
+const (
+	maxPrepareTraceLines = 50
+	maxPrepareTraceBytes = 4096
+)
 
+// prepareRequest adds the request described by msg and runs the prepare phase of its oracle
+// script, without charging any fees.
+func prepareRequest(ctx sdk.Context, keeper Keeper, msg MsgRequestData) (RequestID, error) {
 	id, err := keeper.AddRequest(
 		ctx,
 		msg.OracleScriptID,
@@ -289,47 +497,186 @@ func handleMsgRequestData(
 		msg.ExecuteGas,
 		msg.SourcePort,
 		msg.SourceChannel,
+		msg.PriorityFee,
+		msg.Sender,
 	)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
 	env, err := NewExecutionEnvironment(ctx, keeper, id)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
 	script, err := keeper.GetOracleScript(ctx, msg.OracleScriptID)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
 	ctx.GasMeter().ConsumeGas(msg.PrepareGas, "PrepareRequest")
-	_, _, errOwasm := owasm.Execute(&env, script.Code, "prepare", msg.Calldata, msg.PrepareGas)
+	// Trace the execution only in CheckTx and simulation, where the trace helps script
+	// developers debug failures without slowing down or affecting block execution.
+	var tracer *owasm.Tracer
+	if ctx.IsCheckTx() {
+		tracer = &owasm.Tracer{}
+	}
+	prepareStart := time.Now()
+	_, _, errOwasm := owasm.ExecuteWithTracer(
+		&env, script.Code, "prepare", msg.Calldata, msg.PrepareGas,
+		GetVMLimits(ctx, keeper), keeper.GasSchedule(ctx), tracer,
+	)
+	if !ctx.IsCheckTx() {
+		moduleMetrics.OwasmExecutionDuration.With("entry", "prepare").Observe(time.Since(prepareStart).Seconds())
+	}
 	if errOwasm != nil {
-		return nil, sdkerrors.Wrapf(types.ErrBadWasmExecution,
+		if tracer != nil {
+			return 0, sdkerrors.Wrapf(types.ErrBadWasmExecution,
+				"handleMsgRequestData: An error occured while running Owasm prepare.\n%s",
+				tracer.Truncated(maxPrepareTraceLines, maxPrepareTraceBytes),
+			)
+		}
+		return 0, sdkerrors.Wrapf(types.ErrBadWasmExecution,
 			"handleMsgRequestData: An error occured while running Owasm prepare.",
 		)
 	}
 
 	err = keeper.ValidateDataSourceCount(ctx, id)
 	if err != nil {
-		return nil, err
-	}
-
-	err = keeper.PayDataSourceFees(ctx, id, msg.Sender)
-	if err != nil {
-		return nil, err
+		return 0, err
 	}
+	return id, nil
+}
 
-	// Emit request event
+// emitRequest emits the event of a newly created request.
+func emitRequest(ctx sdk.Context, id RequestID) {
 	ctx.EventManager().EmitEvents(sdk.Events{
 		sdk.NewEvent(
 			types.EventTypeRequest,
 			sdk.NewAttribute(types.AttributeKeyID, fmt.Sprintf("%d", id)),
 		),
 	})
-	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+	if !ctx.IsCheckTx() {
+		moduleMetrics.RequestsCreated.Add(1)
+	}
+}
+
+// handleBeginBlock submits the requests of the reference symbols and subscriptions that are due.
+func handleBeginBlock(ctx sdk.Context, keeper Keeper) {
+	requestReferenceSymbols(ctx, keeper)
+	requestSubscriptions(ctx, keeper)
+}
+
+// requestReferenceSymbols submits a request for every reference symbol whose refresh interval
+// has passed, paid for by the reference data module account. A request that cannot be made is
+// skipped until the next interval.
+func requestReferenceSymbols(ctx sdk.Context, keeper Keeper) {
+	requester := keeper.GetReferenceDataAccount()
+	for _, symbol := range keeper.GetDueReferenceSymbols(ctx) {
+		keeper.SetReferenceSymbolLastRequestHeight(ctx, symbol.Symbol, ctx.BlockHeight())
+
+		msg := types.NewMsgRequestData(
+			symbol.OracleScriptID,
+			symbol.Calldata,
+			symbol.RequestedValidatorCount,
+			symbol.SufficientValidatorCount,
+			symbol.Expiration,
+			symbol.PrepareGas,
+			symbol.ExecuteGas,
+			requester,
+			"",
+			"",
+			sdk.Coins{},
+		)
+		cacheCtx, writeCache := ctx.CacheContext()
+		id, err := submitRequest(cacheCtx, keeper, msg)
+		if err != nil {
+			keeper.Logger(ctx).Error("failed to request reference data", "symbol", symbol.Symbol, "err", err)
+			continue
+		}
+		writeCache()
+		keeper.Logger(ctx).Debug("requested reference data", "symbol", symbol.Symbol, "request_id", id)
+		emitRequest(ctx, id)
+	}
+}
+
+// requestSubscriptions submits a request for up to MaxSubscriptionsPerBlock subscriptions
+// scheduled at or before this block, paying the gas used to make the request and the data source
+// fees out of the subscription balance. Subscriptions past their end height or without enough
+// balance are closed and refunded. A request that fails for any other reason is skipped until the
+// next interval.
+func requestSubscriptions(ctx sdk.Context, keeper Keeper) {
+	for _, subscriptionID := range keeper.GetDueSubscriptionIDs(ctx, keeper.MaxSubscriptionsPerBlock(ctx)) {
+		logger := keeper.Logger(ctx).With("subscription_id", subscriptionID)
+		subscription, err := keeper.GetSubscription(ctx, subscriptionID)
+		if err != nil { // should never happen
+			logger.Error("failed to get subscription", "err", err)
+			continue
+		}
+
+		if ctx.BlockHeight() > subscription.EndHeight {
+			closeSubscription(ctx, keeper, subscriptionID, types.SubscriptionClosedEnded)
+			continue
+		}
+
+		msg := types.NewMsgRequestData(
+			subscription.OracleScriptID,
+			subscription.Calldata,
+			subscription.RequestedValidatorCount,
+			subscription.SufficientValidatorCount,
+			subscription.Expiration,
+			subscription.PrepareGas,
+			subscription.ExecuteGas,
+			subscription.Owner,
+			"",
+			"",
+			sdk.Coins{},
+		)
+		// BeginBlock runs on an infinite gas meter, so measure the gas of the request on a fresh
+		// one to charge it to the subscription.
+		cacheCtx, writeCache := ctx.CacheContext()
+		cacheCtx = cacheCtx.WithGasMeter(sdk.NewInfiniteGasMeter())
+		id, err := prepareRequest(cacheCtx, keeper, msg)
+		if err == nil {
+			err = keeper.ChargeSubscriptionGas(cacheCtx, subscriptionID, cacheCtx.GasMeter().GasConsumed())
+		}
+		if err == nil {
+			err = keeper.PaySubscriptionDataSourceFees(cacheCtx, id, subscriptionID)
+		}
+		if sdkerrors.ErrInsufficientFunds.Is(err) {
+			closeSubscription(ctx, keeper, subscriptionID, types.SubscriptionClosedInsufficientBalance)
+			continue
+		}
+		if err != nil {
+			logger.Error("failed to request subscription", "err", err)
+		} else {
+			writeCache()
+			logger.Debug("requested subscription", "request_id", id)
+			emitRequest(ctx, id)
+		}
+
+		// Close the subscription right away once its last request is made, so the owner gets the
+		// remaining balance back without waiting another interval.
+		if subscription.NextHeight+subscription.Interval > subscription.EndHeight {
+			closeSubscription(ctx, keeper, subscriptionID, types.SubscriptionClosedEnded)
+		} else if err := keeper.RescheduleSubscription(ctx, subscriptionID); err != nil { // should never happen
+			logger.Error("failed to reschedule subscription", "err", err)
+		}
+	}
+}
+
+// closeSubscription closes the subscription, refunding its remaining balance, and emits an event
+// with the reason.
+func closeSubscription(ctx sdk.Context, keeper Keeper, subscriptionID types.SubscriptionID, reason string) {
+	if err := keeper.CloseSubscription(ctx, subscriptionID); err != nil { // should never happen
+		keeper.Logger(ctx).Error("failed to close subscription", "subscription_id", subscriptionID, "err", err)
+		return
+	}
+	ctx.EventManager().EmitEvent(sdk.NewEvent(
+		types.EventTypeSubscriptionClosed,
+		sdk.NewAttribute(types.AttributeKeySubscriptionID, fmt.Sprintf("%d", subscriptionID)),
+		sdk.NewAttribute(types.AttributeKeyReason, reason),
+	))
 }
 
 func handleMsgReportData(
@@ -350,6 +697,9 @@ func handleMsgReportData(
 			sdk.NewAttribute(types.AttributeKeyValidator, msg.Validator.String()),
 		),
 	})
+	if !ctx.IsCheckTx() {
+		moduleMetrics.ReportsReceived.Add(1)
+	}
 	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
 }
 
@@ -392,3 +742,54 @@ func handleMsgRemoveOracleAddress(
 	})
 	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
 }
+
+func handleMsgSubscribe(
+	ctx sdk.Context, keeper Keeper, msg MsgSubscribe,
+) (*sdk.Result, error) {
+
+	id, err := keeper.AddSubscription(
+		ctx,
+		msg.Sender,
+		msg.OracleScriptID,
+		msg.Calldata,
+		msg.RequestedValidatorCount,
+		msg.SufficientValidatorCount,
+		msg.Expiration,
+		msg.PrepareGas,
+		msg.ExecuteGas,
+		msg.Interval,
+		msg.EndHeight,
+		msg.Deposit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			types.EventTypeSubscribe,
+			sdk.NewAttribute(types.AttributeKeyID, fmt.Sprintf("%d", id)),
+		),
+	})
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgCancelRequest(
+	ctx sdk.Context, keeper Keeper, msg MsgCancelRequest,
+) (*sdk.Result, error) {
+
+	err := keeper.CancelRequest(ctx, msg.RequestID, msg.Sender)
+	if err != nil {
+		return nil, err
+	}
+	keeper.CleanupReports(ctx, msg.RequestID)
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			types.EventTypeCancelRequest,
+			sdk.NewAttribute(types.AttributeKeyID, fmt.Sprintf("%d", msg.RequestID)),
+		),
+	})
+	emitRequestResolved(ctx, msg.RequestID, types.Cancelled, types.FailureReasonNone, 0, nil, 0)
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
@@ -0,0 +1,329 @@
+package zoracle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/gaia/x/zoracle/internal/keeper"
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+// setOracleScriptFixture sets oracle script 1 to the code of the given Owasm fixture.
+func setOracleScriptFixture(t *testing.T, ctx sdk.Context, k Keeper, fixture string) {
+	code, err := ioutil.ReadFile("../../owasm/res/" + fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := sdk.AccAddress([]byte("owner_______________"))
+	k.SetOracleScript(ctx, 1, types.NewOracleScript(owner, "script", "description", code))
+}
+
+func TestPrepareTraceInCheckTxOnly(t *testing.T) {
+	ctx, k := keeper.CreateTestInput()
+	keeper.CreateTestValidators(ctx, k, 1)
+	// The prepare entry of this fixture traps.
+	setOracleScriptFixture(t, ctx, k, "moresilly.wasm")
+	msg := types.NewMsgRequestData(
+		1, []byte("calldata"), 1, 1, 100, 10000, 10000, sdk.AccAddress([]byte("sender")), "", "", sdk.Coins{},
+	)
+
+	_, err := handleMsgRequestData(ctx.WithIsCheckTx(true), k, msg)
+	if err == nil || !strings.Contains(err.Error(), "gas used:") || !strings.Contains(err.Error(), "trap:") {
+		t.Errorf("CheckTx error %v, expected it to include the trace", err)
+	}
+
+	_, err = handleMsgRequestData(ctx, k, msg)
+	if err == nil || strings.Contains(err.Error(), "gas used:") {
+		t.Errorf("DeliverTx error %v, expected it without the trace", err)
+	}
+}
+
+// submitTestRequest submits a request to oracle script 1 for one validator with the given execute
+// gas, and returns its ID.
+func submitTestRequest(t *testing.T, ctx sdk.Context, k Keeper, executeGas uint64) RequestID {
+	msg := types.NewMsgRequestData(
+		1, []byte("calldata"), 1, 1, 100, 10000, executeGas, sdk.AccAddress([]byte("requester")), "", "",
+		sdk.Coins{},
+	)
+	id, err := submitRequest(ctx, k, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+// setupResolveTest returns a context with a bonded validator and oracle script 1, whose execute
+// entry returns an 8-byte result using 2793 gas.
+func setupResolveTest(t *testing.T) (sdk.Context, Keeper) {
+	ctx, k := keeper.CreateTestInput()
+	keeper.CreateTestValidators(ctx, k, 1)
+	setOracleScriptFixture(t, ctx, k, "main.wasm")
+	return ctx, k
+}
+
+// checkResolved checks that the request was resolved with the given status and failure reason.
+func checkResolved(
+	t *testing.T, ctx sdk.Context, k Keeper, id RequestID, status types.ResolveStatus, reason types.FailureReason,
+) {
+	request, err := k.GetRequest(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.ResolveStatus != status || request.FailureReason != reason {
+		t.Errorf(
+			"request %d resolved %d with reason %s, expected %d with reason %s",
+			id, request.ResolveStatus, request.FailureReason, status, reason,
+		)
+	}
+}
+
+func TestResolveRequestRecordsFailureReason(t *testing.T) {
+	ctx, k := setupResolveTest(t)
+	vmLimits := GetVMLimits(ctx, k)
+
+	succeeded := submitTestRequest(t, ctx, k, 10000)
+	resolvePendingRequest(ctx, k, succeeded, mustGetRequest(t, ctx, k, succeeded), vmLimits)
+	checkResolved(t, ctx, k, succeeded, types.Success, types.FailureReasonNone)
+
+	outOfGas := submitTestRequest(t, ctx, k, 1000)
+	if gasUsed := resolvePendingRequest(ctx, k, outOfGas, mustGetRequest(t, ctx, k, outOfGas), vmLimits); gasUsed > 1000 {
+		t.Errorf("out of gas request used %d gas, expected at most its 1000", gasUsed)
+	}
+	checkResolved(t, ctx, k, outOfGas, types.Failure, types.FailureReasonOutOfGas)
+
+	// The VM already refuses results over the maximum size, so a bad result is resolved directly.
+	badResult := submitTestRequest(t, ctx, k, 10000)
+	resolveRequest(ctx, k, badResult, types.Failure, types.FailureReasonBadResult, 100)
+	checkResolved(t, ctx, k, badResult, types.Failure, types.FailureReasonBadResult)
+
+	expired := submitTestRequest(t, ctx, k, 10000)
+	if err := k.AddPendingRequest(ctx, expired); err != nil {
+		t.Fatal(err)
+	}
+	handleEndBlock(ctx.WithBlockHeight(ctx.BlockHeight()+k.MaxPendingWaitBlocks(ctx)+1), k)
+	checkResolved(t, ctx, k, expired, types.Failure, types.FailureReasonPendingTimeout)
+
+	cancelled := submitTestRequest(t, ctx, k, 10000)
+	if _, err := handleMsgCancelRequest(ctx, k, types.NewMsgCancelRequest(cancelled, sdk.AccAddress([]byte("requester")))); err != nil {
+		t.Fatal(err)
+	}
+	checkResolved(t, ctx, k, cancelled, types.Cancelled, types.FailureReasonNone)
+
+	// A request that never gets sufficient reports expires at its expiration height.
+	unreported := submitTestRequest(t, ctx, k, 10000)
+	handleEndBlock(ctx.WithBlockHeight(mustGetRequest(t, ctx, k, unreported).ExpirationHeight), k)
+	checkResolved(t, ctx, k, unreported, types.Failure, types.FailureReasonExpired)
+}
+
+func TestFailureReasonString(t *testing.T) {
+	names := map[types.FailureReason]string{
+		types.FailureReasonNone:                 "none",
+		types.FailureReasonExecuteGasOverLimit:  "execute_gas_over_limit",
+		types.FailureReasonBadEnvironment:       "bad_environment",
+		types.FailureReasonOracleScriptNotFound: "oracle_script_not_found",
+		types.FailureReasonGasScheduleNotFound:  "gas_schedule_not_found",
+		types.FailureReasonOutOfGas:             "out_of_gas",
+		types.FailureReasonExecutionError:       "execution_error",
+		types.FailureReasonBadResult:            "bad_result",
+		types.FailureReasonPendingTimeout:       "pending_timeout",
+		types.FailureReasonExpired:              "expired",
+		types.FailureReason(-1):                 "unknown",
+	}
+	for reason, name := range names {
+		if reason.String() != name {
+			t.Errorf("reason %d is named %q, expected %q", reason, reason.String(), name)
+		}
+	}
+}
+
+// mustGetRequest returns the request with the given ID.
+func mustGetRequest(t *testing.T, ctx sdk.Context, k Keeper, id RequestID) types.Request {
+	request, err := k.GetRequest(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return request
+}
+
+// requestResolvedAttributes returns the attributes of the request_resolved event of the given
+// request, or nil if no such event was emitted.
+func requestResolvedAttributes(events sdk.Events, id RequestID) map[string]string {
+	for _, event := range events {
+		if event.Type != types.EventTypeRequestResolved {
+			continue
+		}
+		attributes := make(map[string]string)
+		for _, attribute := range event.Attributes {
+			attributes[string(attribute.Key)] = string(attribute.Value)
+		}
+		if attributes[types.AttributeKeyID] == fmt.Sprintf("%d", id) {
+			return attributes
+		}
+	}
+	return nil
+}
+
+// checkRequestResolvedEvent checks that a request_resolved event with the given attributes was
+// emitted for the request.
+func checkRequestResolvedEvent(t *testing.T, ctx sdk.Context, id RequestID, expected map[string]string) {
+	attributes := requestResolvedAttributes(ctx.EventManager().Events(), id)
+	if attributes == nil {
+		t.Errorf("no request_resolved event for request %d", id)
+		return
+	}
+	for key, value := range expected {
+		if attributes[key] != value {
+			t.Errorf("request %d: event attribute %s is %q, expected %q", id, key, attributes[key], value)
+		}
+	}
+}
+
+func TestRequestResolvedEvents(t *testing.T) {
+	ctx, k := setupResolveTest(t)
+
+	resolved := submitTestRequest(t, ctx, k, 10000)
+	resolveCtx := ctx.WithEventManager(sdk.NewEventManager())
+	gasUsed := resolvePendingRequest(resolveCtx, k, resolved, mustGetRequest(t, ctx, k, resolved), GetVMLimits(ctx, k))
+	result, err := k.GetResult(ctx, resolved, 1, []byte("calldata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resultHash := sha256.Sum256(result.Data)
+	checkRequestResolvedEvent(t, resolveCtx, resolved, map[string]string{
+		types.AttributeKeyResolveStatus:  fmt.Sprintf("%d", types.Success),
+		types.AttributeKeyFailureReason:  "none",
+		types.AttributeKeyGasUsed:        fmt.Sprintf("%d", gasUsed),
+		types.AttributeKeyResultHash:     hex.EncodeToString(resultHash[:]),
+		types.AttributeKeyPacketSequence: "0",
+	})
+
+	expired := submitTestRequest(t, ctx, k, 10000)
+	if err := k.AddPendingRequest(ctx, expired); err != nil {
+		t.Fatal(err)
+	}
+	expireCtx := ctx.WithBlockHeight(ctx.BlockHeight() + k.MaxPendingWaitBlocks(ctx) + 1).
+		WithEventManager(sdk.NewEventManager())
+	handleEndBlock(expireCtx, k)
+	checkRequestResolvedEvent(t, expireCtx, expired, map[string]string{
+		types.AttributeKeyResolveStatus: fmt.Sprintf("%d", types.Failure),
+		types.AttributeKeyFailureReason: "pending_timeout",
+		types.AttributeKeyGasUsed:       "0",
+		types.AttributeKeyResultHash:    "",
+	})
+
+	unreported := submitTestRequest(t, ctx, k, 10000)
+	unreportedCtx := ctx.WithBlockHeight(mustGetRequest(t, ctx, k, unreported).ExpirationHeight).
+		WithEventManager(sdk.NewEventManager())
+	handleEndBlock(unreportedCtx, k)
+	checkRequestResolvedEvent(t, unreportedCtx, unreported, map[string]string{
+		types.AttributeKeyResolveStatus: fmt.Sprintf("%d", types.Failure),
+		types.AttributeKeyFailureReason: "expired",
+		types.AttributeKeyGasUsed:       "0",
+		types.AttributeKeyResultHash:    "",
+	})
+
+	cancelled := submitTestRequest(t, ctx, k, 10000)
+	cancelCtx := ctx.WithEventManager(sdk.NewEventManager())
+	msg := types.NewMsgCancelRequest(cancelled, sdk.AccAddress([]byte("requester")))
+	if _, err := handleMsgCancelRequest(cancelCtx, k, msg); err != nil {
+		t.Fatal(err)
+	}
+	checkRequestResolvedEvent(t, cancelCtx, cancelled, map[string]string{
+		types.AttributeKeyResolveStatus: fmt.Sprintf("%d", types.Cancelled),
+		types.AttributeKeyFailureReason: "none",
+		types.AttributeKeyGasUsed:       "0",
+		types.AttributeKeyResultHash:    "",
+	})
+}
+
+// endBlockAt runs EndBlock at the given height and returns the IDs of the requests it resolved,
+// in the order they were resolved.
+func endBlockAt(t *testing.T, ctx sdk.Context, k Keeper, height int64) []RequestID {
+	ctx = ctx.WithBlockHeight(height).WithEventManager(sdk.NewEventManager())
+	handleEndBlock(ctx, k)
+	var resolved []RequestID
+	for _, event := range ctx.EventManager().Events() {
+		if event.Type != types.EventTypeRequestResolved {
+			continue
+		}
+		for _, attribute := range event.Attributes {
+			if string(attribute.Key) != types.AttributeKeyID {
+				continue
+			}
+			id, err := strconv.ParseInt(string(attribute.Value), 10, 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resolved = append(resolved, RequestID(id))
+		}
+	}
+	return resolved
+}
+
+// addPendingTestRequests submits requests with the given execute gas and adds them to the pending
+// list at the given height.
+func addPendingTestRequests(t *testing.T, ctx sdk.Context, k Keeper, height int64, executeGas ...uint64) []RequestID {
+	var ids []RequestID
+	for _, gas := range executeGas {
+		id := submitTestRequest(t, ctx, k, gas)
+		if err := k.AddPendingRequest(ctx.WithBlockHeight(height), id); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func checkResolvedIDs(t *testing.T, height int64, resolved, expected []RequestID) {
+	if fmt.Sprint(resolved) != fmt.Sprint(expected) {
+		t.Errorf("block %d resolved %v, expected %v", height, resolved, expected)
+	}
+}
+
+func TestEndBlockSkipsRequestsThatDoNotFit(t *testing.T) {
+	ctx, k := setupResolveTest(t)
+	// Each request uses about 3000 gas, but is counted at its execute gas until it has run.
+	k.SetEndBlockExecuteGasLimit(ctx, 12000)
+	ids := addPendingTestRequests(t, ctx, k, 1, 10000, 10000, 5000)
+
+	// The second request does not fit after the first, but the smaller third one still does.
+	checkResolvedIDs(t, 1, endBlockAt(t, ctx, k, 1), []RequestID{ids[0], ids[2]})
+	checkResolvedIDs(t, 2, endBlockAt(t, ctx, k, 2), []RequestID{ids[1]})
+	if pending := k.GetPendingResolveList(ctx); len(pending) != 0 {
+		t.Errorf("pending requests %v, expected none", pending)
+	}
+}
+
+func TestEndBlockAgedRequestBlocksLaterRequests(t *testing.T) {
+	ctx, k := setupResolveTest(t)
+	k.SetEndBlockExecuteGasLimit(ctx, 12000)
+	k.SetPendingAgingBlocks(ctx, 10)
+	aged := addPendingTestRequests(t, ctx, k, 1, 10000, 10000)
+	fresh := addPendingTestRequests(t, ctx, k, 11, 5000)
+
+	// The second aged request does not fit after the first, so the fresh request has to wait even
+	// though it fits.
+	checkResolvedIDs(t, 11, endBlockAt(t, ctx, k, 11), []RequestID{aged[0]})
+	checkResolvedIDs(t, 12, endBlockAt(t, ctx, k, 12), []RequestID{aged[1], fresh[0]})
+}
+
+func TestEndBlockFailsRequestsPastMaxWait(t *testing.T) {
+	ctx, k := setupResolveTest(t)
+	k.SetEndBlockExecuteGasLimit(ctx, 12000)
+	k.SetPendingAgingBlocks(ctx, 10)
+	k.SetMaxPendingWaitBlocks(ctx, 14)
+	ids := addPendingTestRequests(t, ctx, k, 1, 10000, 10000)
+
+	checkResolvedIDs(t, 15, endBlockAt(t, ctx, k, 15), []RequestID{ids[0]})
+	checkResolved(t, ctx, k, ids[0], types.Success, types.FailureReasonNone)
+	// The blocked request has now waited longer than allowed.
+	checkResolvedIDs(t, 16, endBlockAt(t, ctx, k, 16), []RequestID{ids[1]})
+	checkResolved(t, ctx, k, ids[1], types.Failure, types.FailureReasonPendingTimeout)
+}
@@ -0,0 +1,48 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+// An assignment is an open request that one of its requested validators has yet to report to.
+// Assignments are added along with the request and removed once the validator reports, the
+// request gets resolved or cancelled, or the request expires.
+
+// deleteAssignment removes the assignment of the request to the validator.
+func (k Keeper) deleteAssignment(ctx sdk.Context, validator sdk.ValAddress, id types.RequestID) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(types.ValidatorAssignmentStoreKey(validator, id))
+}
+
+// deleteAssignments removes the assignments of the request to all of its requested validators.
+func (k Keeper) deleteAssignments(ctx sdk.Context, id types.RequestID, request types.Request) {
+	for _, validator := range request.RequestedValidators {
+		k.deleteAssignment(ctx, validator, id)
+	}
+}
+
+// GetPendingAssignments returns the IDs of at most limit open requests that the validator has yet
+// to report to, starting from startID in ascending order. Requests that already expired are left
+// out.
+func (k Keeper) GetPendingAssignments(
+	ctx sdk.Context, validator sdk.ValAddress, startID types.RequestID, limit int,
+) []types.RequestID {
+	store := ctx.KVStore(k.storeKey)
+	iterator := store.Iterator(
+		types.ValidatorAssignmentStoreKey(validator, startID),
+		sdk.PrefixEndBytes(types.ValidatorAssignmentsStoreKeyPrefix(validator)),
+	)
+	defer iterator.Close()
+
+	ids := make([]types.RequestID, 0)
+	for ; iterator.Valid() && len(ids) < limit; iterator.Next() {
+		id := types.GetRequestIDFromIndexKey(iterator.Key())
+		request, err := k.GetRequest(ctx, id)
+		if err != nil || request.ExpirationHeight < ctx.BlockHeight() {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
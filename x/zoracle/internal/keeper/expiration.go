@@ -0,0 +1,83 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+// scheduleExpiration adds the open request to the expiration index at its expiration height.
+func (k Keeper) scheduleExpiration(ctx sdk.Context, id types.RequestID, request types.Request) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.RequestExpirationStoreKey(request.ExpirationHeight, id), []byte{})
+}
+
+// unscheduleExpiration removes the request from the expiration index.
+func (k Keeper) unscheduleExpiration(ctx sdk.Context, id types.RequestID, request types.Request) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(types.RequestExpirationStoreKey(request.ExpirationHeight, id))
+}
+
+// ExpireRequests handles the open requests whose expiration height is at or before the current
+// block, which can no longer receive reports after this block, and returns the IDs of the requests
+// expired. Each request that is not waiting in the pending list is marked as expired and resolved
+// with Failure status and FailureReasonExpired, which removes its assignments and moves it out of
+// the open status index, and gets its priority fee refunded. Pending requests are left to be
+// resolved, or to time out, from the pending list.
+func (k Keeper) ExpireRequests(ctx sdk.Context) []types.RequestID {
+	store := ctx.KVStore(k.storeKey)
+	iterator := store.Iterator(
+		types.RequestExpirationStoreKeyPrefix,
+		types.RequestExpirationStoreKey(ctx.BlockHeight()+1, 0),
+	)
+	var keys [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, iterator.Key())
+	}
+	iterator.Close()
+
+	var expired []types.RequestID
+	for _, key := range keys {
+		store.Delete(key)
+		id := types.GetRequestIDFromExpirationKey(key)
+		request, err := k.GetRequest(ctx, id)
+		if err != nil || request.ResolveStatus != types.Open || request.Expired || k.IsPendingRequest(ctx, id) {
+			continue
+		}
+
+		request.Expired = true
+		k.SetRequest(ctx, id, request)
+		if err := k.SetResolve(ctx, id, types.Failure, types.FailureReasonExpired); err != nil { // should never happen
+			k.Logger(ctx).Error("failed to resolve expired request", "request_id", id, "err", err)
+		}
+		if err := k.RefundPriorityFee(ctx, id); err != nil { // should never happen
+			k.Logger(ctx).Error("failed to refund priority fee", "request_id", id, "err", err)
+		}
+		expired = append(expired, id)
+	}
+	return expired
+}
+
+// MigrateAssignments adds the assignments and the expiration of every open request that can still
+// receive reports and was stored before assignments existed. Each requested validator that has not
+// reported yet is assigned the request.
+func (k Keeper) MigrateAssignments(ctx sdk.Context) {
+	store := ctx.KVStore(k.storeKey)
+	requestCount := types.RequestID(k.GetRequestCount(ctx))
+	for id := types.RequestID(1); id <= requestCount; id++ {
+		request, err := k.GetRequest(ctx, id)
+		if err != nil || request.ResolveStatus != types.Open || request.ExpirationHeight < ctx.BlockHeight() {
+			continue
+		}
+
+		received := make(map[string]bool)
+		for _, validator := range request.ReceivedValidators {
+			received[validator.String()] = true
+		}
+		for _, validator := range request.RequestedValidators {
+			if !received[validator.String()] {
+				store.Set(types.ValidatorAssignmentStoreKey(validator, id), []byte{})
+			}
+		}
+		k.scheduleExpiration(ctx, id, request)
+	}
+}
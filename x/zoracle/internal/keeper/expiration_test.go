@@ -0,0 +1,185 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+// setAssignedTestRequest stores, indexes and assigns an open request to the given validators that
+// expires at the given height, with its priority fee escrowed from the requester.
+func setAssignedTestRequest(
+	t *testing.T, ctx sdk.Context, keeper Keeper, validators []sdk.ValAddress, expirationHeight int64,
+	priorityFee sdk.Coins,
+) types.RequestID {
+	requester := sdk.AccAddress([]byte("requester"))
+	if !priorityFee.IsZero() {
+		FundTestAccount(ctx, keeper, requester, keeper.CoinKeeper.GetAllBalances(ctx, requester).Add(priorityFee...))
+		if err := keeper.EscrowPriorityFee(ctx, requester, priorityFee); err != nil {
+			t.Fatal(err)
+		}
+	}
+	id := keeper.GetNextRequestID(ctx)
+	request := types.NewRequest(
+		1, []byte("calldata"), validators, 1, ctx.BlockHeight(), 0, expirationHeight, 1000, "", "", 0,
+		priorityFee, requester,
+	)
+	keeper.SetRequest(ctx, id, request)
+	keeper.indexRequest(ctx, id, request)
+	return id
+}
+
+func TestExpireRequestsDeletesAssignmentsAndRefunds(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	bondDenom := keeper.StakingKeeper.BondDenom(ctx)
+	requester := sdk.AccAddress([]byte("requester"))
+	validator := sdk.ValAddress([]byte("validator"))
+	fee := sdk.NewCoins(sdk.NewInt64Coin(bondDenom, 10))
+	id := setAssignedTestRequest(t, ctx, keeper, []sdk.ValAddress{validator}, 10, fee)
+
+	// The request can still receive reports in the block of its expiration height.
+	if expired := keeper.ExpireRequests(ctx.WithBlockHeight(9)); len(expired) != 0 {
+		t.Fatalf("expired %v before the expiration height, expected none", expired)
+	}
+	if ids := keeper.GetPendingAssignments(ctx.WithBlockHeight(9), validator, 1, 10); len(ids) != 1 {
+		t.Fatalf("pending assignments %v, expected [%d]", ids, id)
+	}
+
+	ctx = ctx.WithBlockHeight(10)
+	if expired := keeper.ExpireRequests(ctx); !equalRequestIDs(expired, []types.RequestID{id}) {
+		t.Fatalf("expired %v, expected [%d]", expired, id)
+	}
+	store := ctx.KVStore(keeper.storeKey)
+	if store.Has(types.ValidatorAssignmentStoreKey(validator, id)) {
+		t.Error("expected the assignment of the expired request to be deleted")
+	}
+	request, _ := keeper.GetRequest(ctx, id)
+	if !request.Expired || request.ResolveStatus != types.Failure || request.FailureReason != types.FailureReasonExpired {
+		t.Errorf(
+			"expired %t with resolve status %d and reason %s, expected an expired failure",
+			request.Expired, request.ResolveStatus, request.FailureReason,
+		)
+	}
+	// The expired request is no longer searchable as open.
+	for status, expected := range map[string][]types.RequestID{"open": {}, "failure": {id}} {
+		params := types.QueryRequestsParams{StartID: 1, Limit: 10, ResolveStatus: status}
+		if ids, _ := keeper.SearchRequests(ctx, params); !equalRequestIDs(ids, expected) {
+			t.Errorf("%s requests %v, expected %v", status, ids, expected)
+		}
+	}
+	if balance := keeper.CoinKeeper.GetBalance(ctx, requester, bondDenom); !balance.Amount.Equal(sdk.NewInt(10)) {
+		t.Errorf("requester has %s, expected the priority fee back", balance)
+	}
+	if expired := keeper.ExpireRequests(ctx.WithBlockHeight(11)); len(expired) != 0 {
+		t.Errorf("expired %v again, expected none", expired)
+	}
+
+	// Pruning the expired request does not refund the priority fee a second time.
+	FundTestAccount(ctx, keeper, keeper.SupplyKeeper.GetModuleAddress(types.ModuleName), fee)
+	keeper.SetResultRetentionBlocks(ctx, 1)
+	if pruned := keeper.PruneRequests(ctx.WithBlockHeight(20)); pruned != 1 {
+		t.Fatalf("pruned %d requests, expected 1", pruned)
+	}
+	if balance := keeper.CoinKeeper.GetBalance(ctx, requester, bondDenom); !balance.Amount.Equal(sdk.NewInt(10)) {
+		t.Errorf("requester has %s after pruning, expected the priority fee to be refunded once", balance)
+	}
+}
+
+func TestExpireRequestsSkipsPendingAndResolvedRequests(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	validator := sdk.ValAddress([]byte("validator"))
+	pending := setAssignedTestRequest(t, ctx, keeper, []sdk.ValAddress{validator}, 10, sdk.Coins{})
+	if err := keeper.AddPendingRequest(ctx, pending); err != nil {
+		t.Fatal(err)
+	}
+	resolved := setAssignedTestRequest(t, ctx, keeper, []sdk.ValAddress{validator}, 10, sdk.Coins{})
+	if err := keeper.SetResolve(ctx, resolved, types.Success, types.FailureReasonNone); err != nil {
+		t.Fatal(err)
+	}
+	store := ctx.KVStore(keeper.storeKey)
+	if store.Has(types.RequestExpirationStoreKey(10, resolved)) {
+		t.Error("expected the expiration of the resolved request to be unscheduled")
+	}
+
+	ctx = ctx.WithBlockHeight(10)
+	if expired := keeper.ExpireRequests(ctx); len(expired) != 0 {
+		t.Errorf("expired %v, expected none", expired)
+	}
+	if request, _ := keeper.GetRequest(ctx, pending); request.Expired {
+		t.Error("expected the pending request to be left to the pending list")
+	}
+	if !store.Has(types.ValidatorAssignmentStoreKey(validator, pending)) {
+		t.Error("expected the assignment of the pending request to be kept until it is resolved")
+	}
+}
+
+func TestGetPendingAssignmentsPaging(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	validator := sdk.ValAddress([]byte("validator"))
+	other := sdk.ValAddress([]byte("other"))
+	for i := 0; i < 5; i++ {
+		validators := []sdk.ValAddress{validator}
+		if i == 2 {
+			validators = []sdk.ValAddress{other}
+		}
+		setAssignedTestRequest(t, ctx, keeper, validators, 10, sdk.Coins{})
+	}
+
+	cases := []struct {
+		startID  types.RequestID
+		limit    int
+		expected []types.RequestID
+	}{
+		{1, 2, []types.RequestID{1, 2}},
+		{3, 2, []types.RequestID{4, 5}},
+		{5, 10, []types.RequestID{5}},
+		{6, 10, []types.RequestID{}},
+	}
+	for _, c := range cases {
+		ids := keeper.GetPendingAssignments(ctx, validator, c.startID, c.limit)
+		if !equalRequestIDs(ids, c.expected) {
+			t.Errorf("from %d with limit %d: %v, expected %v", c.startID, c.limit, ids, c.expected)
+		}
+	}
+}
+
+func TestMigrateAssignments(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	ctx = ctx.WithBlockHeight(10)
+	reported := sdk.ValAddress([]byte("reported"))
+	waiting := sdk.ValAddress([]byte("waiting"))
+	validators := []sdk.ValAddress{reported, waiting}
+	// Requests stored before assignments existed: open, expired and resolved.
+	for id, expirationHeight := range map[types.RequestID]int64{1: 20, 2: 9, 3: 20} {
+		request := types.NewRequest(
+			1, []byte("calldata"), validators, 2, 1, 0, expirationHeight, 1000, "", "", 0, sdk.Coins{}, nil,
+		)
+		request.ReceivedValidators = []sdk.ValAddress{reported}
+		if id == 3 {
+			request.ResolveStatus = types.Success
+		}
+		keeper.SetRequest(ctx, id, request)
+		keeper.GetNextRequestID(ctx)
+	}
+
+	keeper.MigrateAssignments(ctx)
+
+	if ids := keeper.GetPendingAssignments(ctx, waiting, 1, 10); !equalRequestIDs(ids, []types.RequestID{1}) {
+		t.Errorf("assignments of the waiting validator %v, expected [1]", ids)
+	}
+	if ids := keeper.GetPendingAssignments(ctx, reported, 1, 10); len(ids) != 0 {
+		t.Errorf("assignments of the validator that reported %v, expected none", ids)
+	}
+	store := ctx.KVStore(keeper.storeKey)
+	if !store.Has(types.RequestExpirationStoreKey(20, 1)) {
+		t.Error("expected the expiration of the open request to be scheduled")
+	}
+	if store.Has(types.ValidatorAssignmentStoreKey(waiting, 2)) || store.Has(types.RequestExpirationStoreKey(9, 2)) {
+		t.Error("expected the expired request not to be assigned")
+	}
+
+	if expired := keeper.ExpireRequests(ctx.WithBlockHeight(20)); !equalRequestIDs(expired, []types.RequestID{1}) {
+		t.Errorf("expired %v, expected the migrated request to expire", expired)
+	}
+}
@@ -0,0 +1,93 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/gaia/owasm"
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+// LegacyGasScheduleVersion is the gas schedule version of the requests made before gas schedules
+// were recorded, which are executed with the legacy gas schedule.
+const LegacyGasScheduleVersion = 0
+
+// RecordGasSchedule saves the given gas schedule so that requests made under it can later be
+// executed with the same costs, and returns its version. Versions are assigned in recording
+// order and identify schedules by their hash, so recording the same costs again returns the
+// existing version.
+func (k Keeper) RecordGasSchedule(ctx sdk.Context, schedule owasm.GasSchedule) uint64 {
+	store := ctx.KVStore(k.storeKey)
+	hash := schedule.Hash()
+	if bz := store.Get(types.GasScheduleVersionStoreKey(hash)); bz != nil {
+		var version uint64
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &version)
+		return version
+	}
+
+	version := k.GetGasScheduleCount(ctx) + 1
+	store.Set(types.GasScheduleCountStoreKey, k.cdc.MustMarshalBinaryLengthPrefixed(version))
+	store.Set(types.GasScheduleVersionStoreKey(hash), k.cdc.MustMarshalBinaryLengthPrefixed(version))
+	store.Set(types.GasScheduleStoreKey(version), k.cdc.MustMarshalBinaryBare(schedule))
+	return version
+}
+
+// RecordCurrentGasSchedule records the gas schedule parameter and saves its version as the one
+// that new requests are made under. It must be called whenever the parameter changes.
+func (k Keeper) RecordCurrentGasSchedule(ctx sdk.Context) {
+	version := k.RecordGasSchedule(ctx, k.GasSchedule(ctx))
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.CurrentGasScheduleVersionStoreKey, k.cdc.MustMarshalBinaryLengthPrefixed(version))
+}
+
+// RecordModifiedGasSchedule records the gas schedule parameter if it was set in the current block.
+// Governance proposals set parameters without going through the keeper, so EndBlock calls this to
+// catch their changes without decoding the schedule in every block.
+func (k Keeper) RecordModifiedGasSchedule(ctx sdk.Context) {
+	if k.ParamSpace.Modified(ctx, types.KeyGasSchedule) {
+		k.RecordCurrentGasSchedule(ctx)
+	}
+}
+
+// GetCurrentGasScheduleVersion returns the version of the gas schedule parameter as of its last
+// change, or the legacy version if the parameter was never recorded.
+func (k Keeper) GetCurrentGasScheduleVersion(ctx sdk.Context) uint64 {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.CurrentGasScheduleVersionStoreKey)
+	if bz == nil {
+		return LegacyGasScheduleVersion
+	}
+	var version uint64
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &version)
+	return version
+}
+
+// GetGasScheduleCount returns the number of gas schedules ever recorded.
+func (k Keeper) GetGasScheduleCount(ctx sdk.Context) uint64 {
+	var count uint64
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.GasScheduleCountStoreKey)
+	if bz == nil {
+		return 0
+	}
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &count)
+	return count
+}
+
+// GetGasScheduleByVersion returns the gas schedule recorded under the given version. The legacy
+// version always returns the legacy gas schedule.
+func (k Keeper) GetGasScheduleByVersion(ctx sdk.Context, version uint64) (owasm.GasSchedule, error) {
+	if version == LegacyGasScheduleVersion {
+		return owasm.LegacyGasSchedule(), nil
+	}
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.GasScheduleStoreKey(version))
+	if bz == nil {
+		return owasm.GasSchedule{}, sdkerrors.Wrapf(types.ErrItemNotFound,
+			"GetGasScheduleByVersion: Unknown gas schedule version %d.", version,
+		)
+	}
+
+	var schedule owasm.GasSchedule
+	k.cdc.MustUnmarshalBinaryBare(bz, &schedule)
+	return schedule, nil
+}
@@ -0,0 +1,85 @@
+package keeper
+
+import (
+	"testing"
+
+	"github.com/cosmos/gaia/owasm"
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+func TestRecordGasScheduleVersionsByHash(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	schedule := owasm.DefaultGasSchedule()
+
+	if version := keeper.RecordGasSchedule(ctx, schedule); version != 1 {
+		t.Fatalf("first schedule got version %d, expected 1", version)
+	}
+	if version := keeper.RecordGasSchedule(ctx, owasm.DefaultGasSchedule()); version != 1 {
+		t.Errorf("recording the same costs again got version %d, expected 1", version)
+	}
+
+	// A governance change of the costs gets a new version without anyone bumping it.
+	changed := owasm.DefaultGasSchedule()
+	changed.HostCosts[0].BaseCost++
+	if version := keeper.RecordGasSchedule(ctx, changed); version != 2 {
+		t.Errorf("changed schedule got version %d, expected 2", version)
+	}
+	if version := keeper.RecordGasSchedule(ctx, schedule); version != 1 {
+		t.Errorf("reverting to the first schedule got version %d, expected 1", version)
+	}
+
+	recorded, err := keeper.GetGasScheduleByVersion(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorded.Hash() != changed.Hash() {
+		t.Error("version 2 does not return the changed schedule")
+	}
+	if _, err := keeper.GetGasScheduleByVersion(ctx, 3); err == nil {
+		t.Error("expected an unknown version to return an error")
+	}
+}
+
+func TestGetGasScheduleByLegacyVersion(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	schedule, err := keeper.GetGasScheduleByVersion(ctx, LegacyGasScheduleVersion)
+	if err != nil {
+		t.Fatalf("requests made before the upgrade must have a gas schedule: %v", err)
+	}
+	if schedule.Hash() != owasm.LegacyGasSchedule().Hash() {
+		t.Error("the legacy version does not return the legacy gas schedule")
+	}
+	for _, cost := range schedule.HostCosts {
+		if cost.BaseCost != 0 || cost.CostPerByte != 0 {
+			t.Errorf("%s is not free under the legacy gas schedule", cost.Function)
+		}
+	}
+}
+
+func TestCurrentGasScheduleVersion(t *testing.T) {
+	ctx, keeper := createTestInputWithoutParams()
+	if version := keeper.GetCurrentGasScheduleVersion(ctx); version != LegacyGasScheduleVersion {
+		t.Errorf("version %d before the parameter is recorded, expected the legacy version", version)
+	}
+	keeper.SetParams(ctx, types.DefaultParams())
+	if version := keeper.GetCurrentGasScheduleVersion(ctx); version != 1 {
+		t.Fatalf("version %d after setting the parameters, expected 1", version)
+	}
+
+	// A governance proposal sets the parameter directly, and EndBlock records the change.
+	changed := owasm.DefaultGasSchedule()
+	changed.HostCosts[0].BaseCost++
+	keeper.ParamSpace.Set(ctx, types.KeyGasSchedule, changed)
+	if version := keeper.GetCurrentGasScheduleVersion(ctx); version != 1 {
+		t.Errorf("version %d before the change is recorded, expected 1", version)
+	}
+	keeper.RecordModifiedGasSchedule(ctx)
+	if version := keeper.GetCurrentGasScheduleVersion(ctx); version != 2 {
+		t.Errorf("version %d after the change is recorded, expected 2", version)
+	}
+
+	keeper.SetGasSchedule(ctx, owasm.DefaultGasSchedule())
+	if version := keeper.GetCurrentGasScheduleVersion(ctx); version != 1 {
+		t.Errorf("version %d after reverting the parameter, expected 1", version)
+	}
+}
@@ -1,42 +1,129 @@
 package keeper
 
 import (
+	"fmt"
+	"reflect"
+
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/bank"
 	"github.com/cosmos/cosmos-sdk/x/params"
 	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
 	"github.com/cosmos/cosmos-sdk/x/staking"
+	"github.com/cosmos/gaia/owasm"
 	"github.com/cosmos/gaia/x/zoracle/internal/types"
+	"github.com/tendermint/tendermint/libs/log"
 )
 
 type Keeper struct {
-	storeKey      sdk.StoreKey
-	cdc           *codec.Codec
-	CoinKeeper    bank.Keeper
-	StakingKeeper staking.Keeper
-	ChannelKeeper types.ChannelKeeper
-	ParamSpace    params.Subspace
+	storeKey         sdk.StoreKey
+	cdc              *codec.Codec
+	CoinKeeper       bank.Keeper
+	SupplyKeeper     types.SupplyKeeper
+	StakingKeeper    staking.Keeper
+	ChannelKeeper    types.ChannelKeeper
+	ParamSpace       params.Subspace
+	feeCollectorName string
 }
 
 // NewKeeper creates a new zoracle Keeper instance.
 func NewKeeper(
-	cdc *codec.Codec, key sdk.StoreKey, coinKeeper bank.Keeper,
+	cdc *codec.Codec, key sdk.StoreKey, coinKeeper bank.Keeper, supplyKeeper types.SupplyKeeper,
 	stakingKeeper staking.Keeper, channelKeeper types.ChannelKeeper, paramSpace params.Subspace,
+	feeCollectorName string,
 ) Keeper {
 	return Keeper{
-		storeKey:      key,
-		cdc:           cdc,
-		CoinKeeper:    coinKeeper,
-		StakingKeeper: stakingKeeper,
-		ChannelKeeper: channelKeeper,
-		ParamSpace:    paramSpace.WithKeyTable(ParamKeyTable()),
+		storeKey:         key,
+		cdc:              cdc,
+		CoinKeeper:       coinKeeper,
+		SupplyKeeper:     supplyKeeper,
+		StakingKeeper:    stakingKeeper,
+		ChannelKeeper:    channelKeeper,
+		ParamSpace:       paramSpace.WithKeyTable(ParamKeyTable()),
+		feeCollectorName: feeCollectorName,
 	}
 }
 
+// Logger returns a module-specific logger.
+func (keeper Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", fmt.Sprintf("x/%s", types.ModuleName))
+}
+
 // TODO: FIX THIS
 func validateNoOp(_ interface{}) error { return nil }
 
+func validatePositiveUint64(i interface{}) error {
+	v, ok := i.(uint64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if v == 0 {
+		return fmt.Errorf("parameter must be positive: %d", v)
+	}
+	return nil
+}
+
+func validatePositiveInt64(i interface{}) error {
+	v, ok := i.(int64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if v <= 0 {
+		return fmt.Errorf("parameter must be positive: %d", v)
+	}
+	return nil
+}
+
+func validateNonNegativeInt64(i interface{}) error {
+	v, ok := i.(int64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if v < 0 {
+		return fmt.Errorf("parameter must not be negative: %d", v)
+	}
+	return nil
+}
+
+// validateVMLimit returns a validator of an Owasm VM resource limit that must be between 1 and
+// the given upper bound. Limits that depend on each other are checked again on execution.
+func validateVMLimit(max int64) func(interface{}) error {
+	return func(i interface{}) error {
+		v, ok := i.(int64)
+		if !ok {
+			return fmt.Errorf("invalid parameter type: %T", i)
+		}
+		if v <= 0 || v > max {
+			return fmt.Errorf("VM limit must be between 1 and %d: %d", max, v)
+		}
+		return nil
+	}
+}
+
+func validateGasSchedule(i interface{}) error {
+	schedule, ok := i.(owasm.GasSchedule)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	return schedule.Validate()
+}
+
+func validateReportCleanupMode(i interface{}) error {
+	mode, ok := i.(string)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	return types.ValidateReportCleanupMode(mode)
+}
+
+func validateReferenceSymbols(i interface{}) error {
+	symbols, ok := i.(types.ReferenceSymbols)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	return symbols.Validate()
+}
+
 // ParamKeyTable returns the parameter key table for zoracle module.
 func ParamKeyTable() params.KeyTable {
 	return paramtypes.NewKeyTable(
@@ -50,6 +137,23 @@ func ParamKeyTable() params.KeyTable {
 		paramtypes.NewParamSetPair(types.KeyMaxNameLength, types.DefaultMaxNameLength, validateNoOp),
 		paramtypes.NewParamSetPair(types.KeyMaxDescriptionLength, types.DefaultMaxDescriptionLength, validateNoOp),
 		paramtypes.NewParamSetPair(types.KeyGasPerRawDataRequestPerValidator, types.DefaultGasPerRawDataRequestPerValidator, validateNoOp),
+		paramtypes.NewParamSetPair(types.KeyMaxMemoryPages, types.DefaultMaxMemoryPages, validateVMLimit(owasm.MaxMemoryPagesBound)),
+		paramtypes.NewParamSetPair(types.KeyMaxTableSize, types.DefaultMaxTableSize, validateVMLimit(owasm.MaxTableSizeBound)),
+		paramtypes.NewParamSetPair(types.KeyMaxValueSlots, types.DefaultMaxValueSlots, validateVMLimit(owasm.MaxValueSlotsBound)),
+		paramtypes.NewParamSetPair(types.KeyMaxCallStackDepth, types.DefaultMaxCallStackDepth, validateVMLimit(owasm.MaxCallStackDepthBound)),
+		paramtypes.NewParamSetPair(types.KeyDefaultMemoryPages, types.DefaultDefaultMemoryPages, validateVMLimit(owasm.MaxMemoryPagesBound)),
+		paramtypes.NewParamSetPair(types.KeyDefaultTableSize, types.DefaultDefaultTableSize, validateVMLimit(owasm.MaxTableSizeBound)),
+		paramtypes.NewParamSetPair(types.KeyGasSchedule, owasm.DefaultGasSchedule(), validateGasSchedule),
+		paramtypes.NewParamSetPair(types.KeyPendingAgingBlocks, types.DefaultPendingAgingBlocks, validatePositiveInt64),
+		paramtypes.NewParamSetPair(types.KeyMaxPendingWaitBlocks, types.DefaultMaxPendingWaitBlocks, validatePositiveInt64),
+		paramtypes.NewParamSetPair(types.KeyResultRetentionBlocks, types.DefaultResultRetentionBlocks, validateNonNegativeInt64),
+		paramtypes.NewParamSetPair(types.KeyResultRetentionRequests, types.DefaultResultRetentionRequests, validateNonNegativeInt64),
+		paramtypes.NewParamSetPair(types.KeyPruneGasLimit, types.DefaultPruneGasLimit, validatePositiveUint64),
+		paramtypes.NewParamSetPair(types.KeyReportCleanupMode, types.DefaultReportCleanupMode, validateReportCleanupMode),
+		paramtypes.NewParamSetPair(types.KeyReferenceSymbols, types.ReferenceSymbols{}, validateReferenceSymbols),
+		paramtypes.NewParamSetPair(types.KeySubscriptionGasPrice, types.DefaultSubscriptionGasPrice, validatePositiveUint64),
+		paramtypes.NewParamSetPair(types.KeyMaxSubscriptionsPerBlock, types.DefaultMaxSubscriptionsPerBlock, validatePositiveInt64),
+		paramtypes.NewParamSetPair(types.KeyMaxSubscriptionDuration, types.DefaultMaxSubscriptionDuration, validatePositiveInt64),
 	)
 }
 
@@ -142,6 +246,160 @@ func (keeper Keeper) SetGasPerRawDataRequestPerValidator(ctx sdk.Context, value
 	keeper.ParamSpace.Set(ctx, types.KeyGasPerRawDataRequestPerValidator, value)
 }
 
+func (keeper Keeper) MaxMemoryPages(ctx sdk.Context) (res int64) {
+	keeper.ParamSpace.Get(ctx, types.KeyMaxMemoryPages, &res)
+	return
+}
+
+func (keeper Keeper) SetMaxMemoryPages(ctx sdk.Context, value int64) {
+	keeper.ParamSpace.Set(ctx, types.KeyMaxMemoryPages, value)
+}
+
+func (keeper Keeper) MaxTableSize(ctx sdk.Context) (res int64) {
+	keeper.ParamSpace.Get(ctx, types.KeyMaxTableSize, &res)
+	return
+}
+
+func (keeper Keeper) SetMaxTableSize(ctx sdk.Context, value int64) {
+	keeper.ParamSpace.Set(ctx, types.KeyMaxTableSize, value)
+}
+
+func (keeper Keeper) MaxValueSlots(ctx sdk.Context) (res int64) {
+	keeper.ParamSpace.Get(ctx, types.KeyMaxValueSlots, &res)
+	return
+}
+
+func (keeper Keeper) SetMaxValueSlots(ctx sdk.Context, value int64) {
+	keeper.ParamSpace.Set(ctx, types.KeyMaxValueSlots, value)
+}
+
+func (keeper Keeper) MaxCallStackDepth(ctx sdk.Context) (res int64) {
+	keeper.ParamSpace.Get(ctx, types.KeyMaxCallStackDepth, &res)
+	return
+}
+
+func (keeper Keeper) SetMaxCallStackDepth(ctx sdk.Context, value int64) {
+	keeper.ParamSpace.Set(ctx, types.KeyMaxCallStackDepth, value)
+}
+
+func (keeper Keeper) DefaultMemoryPages(ctx sdk.Context) (res int64) {
+	keeper.ParamSpace.Get(ctx, types.KeyDefaultMemoryPages, &res)
+	return
+}
+
+func (keeper Keeper) SetDefaultMemoryPages(ctx sdk.Context, value int64) {
+	keeper.ParamSpace.Set(ctx, types.KeyDefaultMemoryPages, value)
+}
+
+func (keeper Keeper) DefaultTableSize(ctx sdk.Context) (res int64) {
+	keeper.ParamSpace.Get(ctx, types.KeyDefaultTableSize, &res)
+	return
+}
+
+func (keeper Keeper) SetDefaultTableSize(ctx sdk.Context, value int64) {
+	keeper.ParamSpace.Set(ctx, types.KeyDefaultTableSize, value)
+}
+
+func (keeper Keeper) GasSchedule(ctx sdk.Context) (res owasm.GasSchedule) {
+	keeper.ParamSpace.Get(ctx, types.KeyGasSchedule, &res)
+	return
+}
+
+func (keeper Keeper) SetGasSchedule(ctx sdk.Context, value owasm.GasSchedule) {
+	keeper.ParamSpace.Set(ctx, types.KeyGasSchedule, value)
+	keeper.RecordCurrentGasSchedule(ctx)
+}
+
+func (keeper Keeper) PendingAgingBlocks(ctx sdk.Context) (res int64) {
+	keeper.ParamSpace.Get(ctx, types.KeyPendingAgingBlocks, &res)
+	return
+}
+
+func (keeper Keeper) SetPendingAgingBlocks(ctx sdk.Context, value int64) {
+	keeper.ParamSpace.Set(ctx, types.KeyPendingAgingBlocks, value)
+}
+
+func (keeper Keeper) MaxPendingWaitBlocks(ctx sdk.Context) (res int64) {
+	keeper.ParamSpace.Get(ctx, types.KeyMaxPendingWaitBlocks, &res)
+	return
+}
+
+func (keeper Keeper) SetMaxPendingWaitBlocks(ctx sdk.Context, value int64) {
+	keeper.ParamSpace.Set(ctx, types.KeyMaxPendingWaitBlocks, value)
+}
+
+func (keeper Keeper) ResultRetentionBlocks(ctx sdk.Context) (res int64) {
+	keeper.ParamSpace.Get(ctx, types.KeyResultRetentionBlocks, &res)
+	return
+}
+
+func (keeper Keeper) SetResultRetentionBlocks(ctx sdk.Context, value int64) {
+	keeper.ParamSpace.Set(ctx, types.KeyResultRetentionBlocks, value)
+}
+
+func (keeper Keeper) ResultRetentionRequests(ctx sdk.Context) (res int64) {
+	keeper.ParamSpace.Get(ctx, types.KeyResultRetentionRequests, &res)
+	return
+}
+
+func (keeper Keeper) SetResultRetentionRequests(ctx sdk.Context, value int64) {
+	keeper.ParamSpace.Set(ctx, types.KeyResultRetentionRequests, value)
+}
+
+func (keeper Keeper) PruneGasLimit(ctx sdk.Context) (res uint64) {
+	keeper.ParamSpace.Get(ctx, types.KeyPruneGasLimit, &res)
+	return
+}
+
+func (keeper Keeper) SetPruneGasLimit(ctx sdk.Context, value uint64) {
+	keeper.ParamSpace.Set(ctx, types.KeyPruneGasLimit, value)
+}
+
+func (keeper Keeper) ReportCleanupMode(ctx sdk.Context) (res string) {
+	keeper.ParamSpace.Get(ctx, types.KeyReportCleanupMode, &res)
+	return
+}
+
+func (keeper Keeper) SetReportCleanupMode(ctx sdk.Context, value string) {
+	keeper.ParamSpace.Set(ctx, types.KeyReportCleanupMode, value)
+}
+
+func (keeper Keeper) ReferenceSymbols(ctx sdk.Context) (res types.ReferenceSymbols) {
+	keeper.ParamSpace.Get(ctx, types.KeyReferenceSymbols, &res)
+	return
+}
+
+func (keeper Keeper) SetReferenceSymbols(ctx sdk.Context, value types.ReferenceSymbols) {
+	keeper.ParamSpace.Set(ctx, types.KeyReferenceSymbols, value)
+}
+
+func (keeper Keeper) SubscriptionGasPrice(ctx sdk.Context) (res uint64) {
+	keeper.ParamSpace.Get(ctx, types.KeySubscriptionGasPrice, &res)
+	return
+}
+
+func (keeper Keeper) SetSubscriptionGasPrice(ctx sdk.Context, value uint64) {
+	keeper.ParamSpace.Set(ctx, types.KeySubscriptionGasPrice, value)
+}
+
+func (keeper Keeper) MaxSubscriptionsPerBlock(ctx sdk.Context) (res int64) {
+	keeper.ParamSpace.Get(ctx, types.KeyMaxSubscriptionsPerBlock, &res)
+	return
+}
+
+func (keeper Keeper) SetMaxSubscriptionsPerBlock(ctx sdk.Context, value int64) {
+	keeper.ParamSpace.Set(ctx, types.KeyMaxSubscriptionsPerBlock, value)
+}
+
+func (keeper Keeper) MaxSubscriptionDuration(ctx sdk.Context) (res int64) {
+	keeper.ParamSpace.Get(ctx, types.KeyMaxSubscriptionDuration, &res)
+	return
+}
+
+func (keeper Keeper) SetMaxSubscriptionDuration(ctx sdk.Context, value int64) {
+	keeper.ParamSpace.Set(ctx, types.KeyMaxSubscriptionDuration, value)
+}
+
 // GetParams returns all current parameters as a types.Params instance.
 func (keeper Keeper) GetParams(ctx sdk.Context) types.Params {
 	return types.NewParams(
@@ -155,9 +413,71 @@ func (keeper Keeper) GetParams(ctx sdk.Context) types.Params {
 		keeper.MaxNameLength(ctx),
 		keeper.MaxDescriptionLength(ctx),
 		keeper.GasPerRawDataRequestPerValidator(ctx),
+		keeper.MaxMemoryPages(ctx),
+		keeper.MaxTableSize(ctx),
+		keeper.MaxValueSlots(ctx),
+		keeper.MaxCallStackDepth(ctx),
+		keeper.DefaultMemoryPages(ctx),
+		keeper.DefaultTableSize(ctx),
+		keeper.GasSchedule(ctx),
+		keeper.PendingAgingBlocks(ctx),
+		keeper.MaxPendingWaitBlocks(ctx),
+		keeper.ResultRetentionBlocks(ctx),
+		keeper.ResultRetentionRequests(ctx),
+		keeper.PruneGasLimit(ctx),
+		keeper.ReportCleanupMode(ctx),
+		keeper.ReferenceSymbols(ctx),
+		keeper.SubscriptionGasPrice(ctx),
+		keeper.MaxSubscriptionsPerBlock(ctx),
+		keeper.MaxSubscriptionDuration(ctx),
 	)
 }
 
+// SetParams sets all parameters from the given types.Params instance.
+func (keeper Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	keeper.SetMaxDataSourceExecutableSize(ctx, params.MaxDataSourceExecutableSize)
+	keeper.SetMaxOracleScriptCodeSize(ctx, params.MaxOracleScriptCodeSize)
+	keeper.SetMaxCalldataSize(ctx, params.MaxCalldataSize)
+	keeper.SetMaxDataSourceCountPerRequest(ctx, params.MaxDataSourceCountPerRequest)
+	keeper.SetMaxRawDataReportSize(ctx, params.MaxRawDataReportSize)
+	keeper.SetMaxResultSize(ctx, params.MaxResultSize)
+	keeper.SetEndBlockExecuteGasLimit(ctx, params.EndBlockExecuteGasLimit)
+	keeper.SetMaxNameLength(ctx, params.MaxNameLength)
+	keeper.SetMaxDescriptionLength(ctx, params.MaxDescriptionLength)
+	keeper.SetGasPerRawDataRequestPerValidator(ctx, params.GasPerRawDataRequestPerValidator)
+	keeper.SetMaxMemoryPages(ctx, params.MaxMemoryPages)
+	keeper.SetMaxTableSize(ctx, params.MaxTableSize)
+	keeper.SetMaxValueSlots(ctx, params.MaxValueSlots)
+	keeper.SetMaxCallStackDepth(ctx, params.MaxCallStackDepth)
+	keeper.SetDefaultMemoryPages(ctx, params.DefaultMemoryPages)
+	keeper.SetDefaultTableSize(ctx, params.DefaultTableSize)
+	keeper.SetGasSchedule(ctx, params.GasSchedule)
+	keeper.SetPendingAgingBlocks(ctx, params.PendingAgingBlocks)
+	keeper.SetMaxPendingWaitBlocks(ctx, params.MaxPendingWaitBlocks)
+	keeper.SetResultRetentionBlocks(ctx, params.ResultRetentionBlocks)
+	keeper.SetResultRetentionRequests(ctx, params.ResultRetentionRequests)
+	keeper.SetPruneGasLimit(ctx, params.PruneGasLimit)
+	keeper.SetReportCleanupMode(ctx, params.ReportCleanupMode)
+	keeper.SetReferenceSymbols(ctx, params.ReferenceSymbols)
+	keeper.SetSubscriptionGasPrice(ctx, params.SubscriptionGasPrice)
+	keeper.SetMaxSubscriptionsPerBlock(ctx, params.MaxSubscriptionsPerBlock)
+	keeper.SetMaxSubscriptionDuration(ctx, params.MaxSubscriptionDuration)
+}
+
+// MigrateParams sets every parameter missing from the store to its default value, so that
+// parameters added since the chain started can be read, and records the gas schedule in use. It
+// leaves parameters that are already set unchanged.
+func (keeper Keeper) MigrateParams(ctx sdk.Context) {
+	defaults := types.DefaultParams()
+	for _, pair := range defaults.ParamSetPairs() {
+		if keeper.ParamSpace.Has(ctx, pair.Key) {
+			continue
+		}
+		keeper.ParamSpace.Set(ctx, pair.Key, reflect.Indirect(reflect.ValueOf(pair.Value)).Interface())
+	}
+	keeper.RecordCurrentGasSchedule(ctx)
+}
+
 // GetRequestCount returns the current number of all requests ever exist.
 func (k Keeper) GetRequestCount(ctx sdk.Context) int64 {
 	var requestNumber int64
@@ -181,6 +501,28 @@ func (k Keeper) GetNextRequestID(ctx sdk.Context) types.RequestID {
 	return types.RequestID(requestNumber + 1)
 }
 
+// GetPendingSequence returns the current enqueue sequence of pending requests.
+func (k Keeper) GetPendingSequence(ctx sdk.Context) uint64 {
+	var sequence uint64
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.PendingSequenceStoreKey)
+	if bz == nil {
+		return 0
+	}
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &sequence)
+	return sequence
+}
+
+// GetNextPendingSequence increments and returns the current enqueue sequence of pending requests.
+func (k Keeper) GetNextPendingSequence(ctx sdk.Context) uint64 {
+	sequence := k.GetPendingSequence(ctx)
+	store := ctx.KVStore(k.storeKey)
+
+	bz := k.cdc.MustMarshalBinaryLengthPrefixed(sequence + 1)
+	store.Set(types.PendingSequenceStoreKey, bz)
+	return sequence + 1
+}
+
 // GetDataSourceCount returns the current number of all data sources ever exist.
 func (k Keeper) GetDataSourceCount(ctx sdk.Context) int64 {
 	var dataSourceCount int64
@@ -226,3 +568,26 @@ func (k Keeper) GetNextOracleScriptID(ctx sdk.Context) types.OracleScriptID {
 	store.Set(types.OracleScriptCountStoreKey, bz)
 	return types.OracleScriptID(oracleScriptCount + 1)
 }
+
+// GetSubscriptionCount returns the current number of all subscriptions ever exist.
+func (k Keeper) GetSubscriptionCount(ctx sdk.Context) int64 {
+	var subscriptionCount int64
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.SubscriptionCountStoreKey)
+	if bz == nil {
+		return 0
+	}
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &subscriptionCount)
+	return subscriptionCount
+}
+
+// GetNextSubscriptionID increments and returns the current number of subscriptions.
+// If the global subscription count is not set, it initializes the value and returns 1.
+func (k Keeper) GetNextSubscriptionID(ctx sdk.Context) types.SubscriptionID {
+	subscriptionCount := k.GetSubscriptionCount(ctx)
+	store := ctx.KVStore(k.storeKey)
+
+	bz := k.cdc.MustMarshalBinaryLengthPrefixed(subscriptionCount + 1)
+	store.Set(types.SubscriptionCountStoreKey, bz)
+	return types.SubscriptionID(subscriptionCount + 1)
+}
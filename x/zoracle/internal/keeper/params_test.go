@@ -0,0 +1,102 @@
+package keeper
+
+import (
+	"testing"
+
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+// TestParamValidation checks that governance updates of the parameters are validated.
+func TestParamValidation(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	cases := []struct {
+		key   []byte
+		value string
+		valid bool
+	}{
+		{types.KeyMaxMemoryPages, `"1024"`, true},
+		{types.KeyMaxMemoryPages, `"0"`, false},
+		{types.KeyMaxMemoryPages, `"-1"`, false},
+		{types.KeyMaxMemoryPages, `"16385"`, false},
+		{types.KeyMaxTableSize, `"0"`, false},
+		{types.KeyMaxTableSize, `"1048577"`, false},
+		{types.KeyMaxValueSlots, `"65536"`, true},
+		{types.KeyMaxValueSlots, `"-65536"`, false},
+		{types.KeyMaxCallStackDepth, `"0"`, false},
+		{types.KeyMaxCallStackDepth, `"16385"`, false},
+		{types.KeyDefaultMemoryPages, `"64"`, true},
+		{types.KeyDefaultMemoryPages, `"0"`, false},
+		{types.KeyDefaultTableSize, `"-1"`, false},
+		{types.KeyPendingAgingBlocks, `"10"`, true},
+		{types.KeyPendingAgingBlocks, `"0"`, false},
+		{types.KeyMaxPendingWaitBlocks, `"100"`, true},
+		{types.KeyMaxPendingWaitBlocks, `"-1"`, false},
+		{types.KeyResultRetentionBlocks, `"0"`, true},
+		{types.KeyResultRetentionBlocks, `"-1"`, false},
+		{types.KeyResultRetentionRequests, `"100"`, true},
+		{types.KeyResultRetentionRequests, `"-100"`, false},
+		{types.KeyPruneGasLimit, `"1000"`, true},
+		{types.KeyPruneGasLimit, `"0"`, false},
+		{types.KeySubscriptionGasPrice, `"1"`, true},
+		{types.KeySubscriptionGasPrice, `"0"`, false},
+		{types.KeyMaxSubscriptionsPerBlock, `"100"`, true},
+		{types.KeyMaxSubscriptionsPerBlock, `"0"`, false},
+		{types.KeyMaxSubscriptionDuration, `"1000"`, true},
+		{types.KeyMaxSubscriptionDuration, `"-1"`, false},
+	}
+	for _, c := range cases {
+		err := keeper.ParamSpace.Update(ctx, c.key, []byte(c.value))
+		if c.valid && err != nil {
+			t.Errorf("%s = %s: unexpected error: %v", c.key, c.value, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("%s = %s: expected an error", c.key, c.value)
+		}
+	}
+}
+
+func TestMigrateParamsSetsMissingParams(t *testing.T) {
+	ctx, keeper := createTestInputWithoutParams()
+	// Only the parameters of the first release are in the store before the upgrade.
+	keeper.SetMaxDataSourceExecutableSize(ctx, 1)
+	keeper.SetMaxOracleScriptCodeSize(ctx, 2)
+	keeper.SetMaxCalldataSize(ctx, 3)
+	keeper.SetMaxDataSourceCountPerRequest(ctx, 4)
+	keeper.SetMaxRawDataReportSize(ctx, 5)
+	keeper.SetMaxResultSize(ctx, 6)
+	keeper.SetEndBlockExecuteGasLimit(ctx, 7)
+	keeper.SetMaxNameLength(ctx, 8)
+	keeper.SetMaxDescriptionLength(ctx, 9)
+	keeper.SetGasPerRawDataRequestPerValidator(ctx, 10)
+
+	keeper.MigrateParams(ctx)
+
+	expected := types.DefaultParams()
+	expected.MaxDataSourceExecutableSize = 1
+	expected.MaxOracleScriptCodeSize = 2
+	expected.MaxCalldataSize = 3
+	expected.MaxDataSourceCountPerRequest = 4
+	expected.MaxRawDataReportSize = 5
+	expected.MaxResultSize = 6
+	expected.EndBlockExecuteGasLimit = 7
+	expected.MaxNameLength = 8
+	expected.MaxDescriptionLength = 9
+	expected.GasPerRawDataRequestPerValidator = 10
+	if params := keeper.GetParams(ctx); params.String() != expected.String() {
+		t.Errorf("params after migration:\n%s\nexpected:\n%s", params, expected)
+	}
+
+	version := keeper.RecordGasSchedule(ctx, expected.GasSchedule)
+	if version != 1 || keeper.GetGasScheduleCount(ctx) != 1 {
+		t.Errorf("default gas schedule has version %d, expected it to be recorded as version 1", version)
+	}
+}
+
+func TestMigrateParamsKeepsExistingParams(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	keeper.SetPendingAgingBlocks(ctx, 42)
+	keeper.MigrateParams(ctx)
+	if keeper.PendingAgingBlocks(ctx) != 42 {
+		t.Errorf("pending aging blocks %d, expected 42", keeper.PendingAgingBlocks(ctx))
+	}
+}
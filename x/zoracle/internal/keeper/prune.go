@@ -0,0 +1,120 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+// GetPruneCursor returns the ID of the next request to consider for pruning.
+func (k Keeper) GetPruneCursor(ctx sdk.Context) types.RequestID {
+	var cursor int64
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.PruneCursorStoreKey)
+	if bz == nil {
+		return 1
+	}
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &cursor)
+	return types.RequestID(cursor)
+}
+
+// SetPruneCursor saves the ID of the next request to consider for pruning.
+func (k Keeper) SetPruneCursor(ctx sdk.Context, cursor types.RequestID) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.PruneCursorStoreKey, k.cdc.MustMarshalBinaryLengthPrefixed(int64(cursor)))
+}
+
+// PruneRequests deletes the raw data requests, raw reports and results of requests that fall
+// outside both the ResultRetentionBlocks and ResultRetentionRequests windows, in request ID order
+// starting from the prune cursor. It stops at the first request that must be kept and once
+// PruneGasLimit store gas has been spent, and returns the number of requests pruned. The latest
+// result of each oracle script and calldata is always kept, and pruned requests keep their
+// resolve status and result hash.
+//
+// A request that is still open and not yet expired, or still pending, stops pruning until it is
+// finished, so that the cursor never has to come back to it. This delays pruning by at most the
+// request expiration plus MaxPendingWaitBlocks, since requests are made in ID order.
+func (k Keeper) PruneRequests(ctx sdk.Context) int {
+	retentionBlocks := k.ResultRetentionBlocks(ctx)
+	retentionRequests := k.ResultRetentionRequests(ctx)
+	if retentionBlocks == 0 && retentionRequests == 0 {
+		return 0
+	}
+
+	gasLimit := k.PruneGasLimit(ctx)
+	pruneCtx := ctx.WithGasMeter(sdk.NewInfiniteGasMeter())
+	requestCount := types.RequestID(k.GetRequestCount(ctx))
+
+	pruned := 0
+	id := k.GetPruneCursor(ctx)
+	for ; id <= requestCount && pruneCtx.GasMeter().GasConsumed() < gasLimit; id++ {
+		request, err := k.GetRequest(pruneCtx, id)
+		if err != nil { // should never happen
+			continue
+		}
+		if retentionRequests > 0 && int64(requestCount-id) < retentionRequests {
+			break
+		}
+		if retentionBlocks > 0 && ctx.BlockHeight()-request.RequestHeight <= retentionBlocks {
+			break
+		}
+		if !k.isFinished(pruneCtx, id, request) {
+			break
+		}
+		k.pruneRequest(pruneCtx, id, request)
+		pruned++
+	}
+	k.SetPruneCursor(ctx, id)
+	return pruned
+}
+
+// isFinished returns whether the request will never be worked on again, because it has been
+// resolved, which includes expiring, or because it is past its expiration height without having
+// been expired, as are requests stored before expirations were scheduled.
+func (k Keeper) isFinished(ctx sdk.Context, id types.RequestID, request types.Request) bool {
+	if request.ResolveStatus != types.Open {
+		return true
+	}
+	return request.ExpirationHeight < ctx.BlockHeight() && !k.IsPendingRequest(ctx, id)
+}
+
+// pruneRequest deletes the raw data requests, raw reports, open assignments and result of the
+// request, except for a result that is the latest one of its oracle script and calldata. The
+// priority fee of a request that is still open past its expiration height is refunded to its
+// requester. Requests expired by ExpireRequests are already resolved and refunded.
+func (k Keeper) pruneRequest(ctx sdk.Context, id types.RequestID, request types.Request) {
+	store := ctx.KVStore(k.storeKey)
+
+	var keys [][]byte
+	for _, prefix := range [][]byte{
+		types.RawDataRequestStoreKeyPrefix,
+		types.RawDataReportStoreKeyPrefix,
+		types.RawDataRequestExternalIDStoreKeyPrefix,
+	} {
+		iterator := sdk.KVStorePrefixIterator(store, types.GetIteratorPrefix(prefix, id))
+		for ; iterator.Valid(); iterator.Next() {
+			keys = append(keys, iterator.Key())
+		}
+		iterator.Close()
+	}
+	for _, key := range keys {
+		store.Delete(key)
+	}
+	store.Delete(types.ReportCommitmentStoreKey(id))
+	k.deleteAssignments(ctx, id, request)
+	k.unscheduleExpiration(ctx, id, request)
+	store.Delete(types.RawDataRequestCountStoreKey(id))
+
+	latestID, err := k.GetLatestResultRequestID(ctx, request.OracleScriptID, request.Calldata)
+	if err != nil || latestID != id {
+		store.Delete(types.ResultStoreKey(id, request.OracleScriptID, request.Calldata))
+	}
+
+	request.Pruned = true
+	k.SetRequest(ctx, id, request)
+
+	if request.ResolveStatus == types.Open && !request.Expired {
+		if err := k.RefundPriorityFee(ctx, id); err != nil { // should never happen
+			k.Logger(ctx).Error("failed to refund priority fee", "request_id", id, "err", err)
+		}
+	}
+}
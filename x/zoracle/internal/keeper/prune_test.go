@@ -0,0 +1,177 @@
+package keeper
+
+import (
+	"bytes"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+// setPruneTestRequest stores a request at the current height with a raw data request, a report
+// and, if resolved, a result for the given calldata.
+func setPruneTestRequest(
+	t *testing.T, ctx sdk.Context, keeper Keeper, calldata string, resolved bool,
+) types.RequestID {
+	owner := sdk.AccAddress([]byte("owner"))
+	validator := sdk.ValAddress([]byte("validator"))
+	keeper.SetDataSource(ctx, 1, types.NewDataSource(owner, "source", "description", sdk.Coins{}, []byte("executable")))
+	id := keeper.GetNextRequestID(ctx)
+	keeper.SetRequest(ctx, id, types.NewRequest(
+		1, []byte(calldata), []sdk.ValAddress{validator}, 1, ctx.BlockHeight(), 0, ctx.BlockHeight()+10,
+		1000, "", "", 0, sdk.Coins{}, owner,
+	))
+	if err := keeper.AddNewRawDataRequest(ctx, id, 1, 1, []byte("calldata")); err != nil {
+		t.Fatal(err)
+	}
+	keeper.SetRawDataReport(ctx, id, 1, validator, types.NewRawDataReport(0, []byte("data")))
+	if resolved {
+		if err := keeper.AddResult(ctx, id, 1, []byte(calldata), []byte{byte(id)}); err != nil {
+			t.Fatal(err)
+		}
+		if err := keeper.SetResolve(ctx, id, types.Success, types.FailureReasonNone); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return id
+}
+
+// isPruned returns whether the request is marked pruned and its raw data is gone.
+func isPruned(t *testing.T, ctx sdk.Context, keeper Keeper, id types.RequestID) bool {
+	request, err := keeper.GetRequest(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hasRawData := keeper.CheckRawDataRequestExists(ctx, id, 1) ||
+		len(keeper.GetRawDataRequestExternalIDs(ctx, id)) != 0
+	if request.Pruned == hasRawData {
+		t.Fatalf("request %d: pruned is %t but raw data exists is %t", id, request.Pruned, hasRawData)
+	}
+	return request.Pruned
+}
+
+func TestPruneRequestsDisabledByDefault(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	id := setPruneTestRequest(t, ctx, keeper, "calldata", true)
+	ctx = ctx.WithBlockHeight(1000)
+	if pruned := keeper.PruneRequests(ctx); pruned != 0 || isPruned(t, ctx, keeper, id) {
+		t.Errorf("pruned %d requests, expected pruning to be disabled", pruned)
+	}
+}
+
+func TestPruneRequestsRetentionBlocks(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	keeper.SetResultRetentionBlocks(ctx, 10)
+	var ids []types.RequestID
+	for height := int64(1); height <= 4; height++ {
+		ids = append(ids, setPruneTestRequest(t, ctx.WithBlockHeight(height), keeper, "calldata", true))
+	}
+
+	// At height 13, requests made at heights 1 and 2 are more than 10 blocks old.
+	ctx = ctx.WithBlockHeight(13)
+	if pruned := keeper.PruneRequests(ctx); pruned != 2 {
+		t.Errorf("pruned %d requests, expected 2", pruned)
+	}
+	for i, expected := range []bool{true, true, false, false} {
+		if isPruned(t, ctx, keeper, ids[i]) != expected {
+			t.Errorf("request %d: pruned is %t, expected %t", ids[i], !expected, expected)
+		}
+	}
+	if keeper.GetPruneCursor(ctx) != ids[2] {
+		t.Errorf("prune cursor %d, expected %d", keeper.GetPruneCursor(ctx), ids[2])
+	}
+}
+
+func TestPruneRequestsRetentionRequests(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	keeper.SetResultRetentionRequests(ctx, 2)
+	var ids []types.RequestID
+	for i := 0; i < 5; i++ {
+		ids = append(ids, setPruneTestRequest(t, ctx, keeper, "calldata", true))
+	}
+	if pruned := keeper.PruneRequests(ctx); pruned != 3 {
+		t.Errorf("pruned %d requests, expected all but the last 2", pruned)
+	}
+
+	// When both windows are set, a request is kept if it is in either one.
+	keeper.SetResultRetentionBlocks(ctx, 10)
+	ids = append(ids, setPruneTestRequest(t, ctx, keeper, "calldata", true))
+	if pruned := keeper.PruneRequests(ctx.WithBlockHeight(5)); pruned != 0 {
+		t.Errorf("pruned %d requests within the block window, expected 0", pruned)
+	}
+	if pruned := keeper.PruneRequests(ctx.WithBlockHeight(100)); pruned != 1 {
+		t.Errorf("pruned %d requests, expected 1", pruned)
+	}
+	for i, expected := range []bool{true, true, true, true, false, false} {
+		if isPruned(t, ctx, keeper, ids[i]) != expected {
+			t.Errorf("request %d: pruned is %t, expected %t", ids[i], !expected, expected)
+		}
+	}
+}
+
+func TestPruneRequestsGasLimit(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	keeper.SetResultRetentionRequests(ctx, 1)
+	for i := 0; i < 11; i++ {
+		setPruneTestRequest(t, ctx, keeper, "calldata", true)
+	}
+
+	// Measure the store gas of pruning a single request.
+	keeper.SetPruneGasLimit(ctx, 1)
+	if pruned := keeper.PruneRequests(ctx); pruned != 1 {
+		t.Fatalf("pruned %d requests with a tiny budget, expected pruning to stop after the first", pruned)
+	}
+
+	keeper.SetPruneGasLimit(ctx, 1000000)
+	if pruned := keeper.PruneRequests(ctx); pruned != 9 {
+		t.Errorf("pruned %d requests, expected pruning to resume with the rest", pruned)
+	}
+	if keeper.GetPruneCursor(ctx) != 11 {
+		t.Errorf("prune cursor %d, expected pruning to resume and reach request 11", keeper.GetPruneCursor(ctx))
+	}
+}
+
+func TestPruneRequestsKeepsLatestResultAndHash(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	keeper.SetResultRetentionRequests(ctx, 1)
+	older := setPruneTestRequest(t, ctx, keeper, "calldata", true)
+	latest := setPruneTestRequest(t, ctx, keeper, "other", true)
+	setPruneTestRequest(t, ctx, keeper, "calldata", true)
+	latestRequest, _ := keeper.GetRequest(ctx, latest)
+
+	if pruned := keeper.PruneRequests(ctx); pruned != 2 {
+		t.Fatalf("pruned %d requests, expected 2", pruned)
+	}
+	if keeper.HasResult(ctx, older, 1, []byte("calldata")) {
+		t.Error("expected the replaced result to be pruned")
+	}
+	if !keeper.HasResult(ctx, latest, 1, []byte("other")) {
+		t.Error("expected the latest result of its calldata to be kept")
+	}
+	request, _ := keeper.GetRequest(ctx, latest)
+	if !request.Pruned || request.ResolveStatus != types.Success {
+		t.Errorf("pruned request has pruned %t and status %d, expected true and success", request.Pruned, request.ResolveStatus)
+	}
+	if !bytes.Equal(request.ResultHash, latestRequest.ResultHash) || len(request.ResultHash) == 0 {
+		t.Error("expected the pruned request to keep its result hash")
+	}
+}
+
+func TestPruneRequestsStopsAtOpenRequest(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	keeper.SetResultRetentionBlocks(ctx, 1)
+	resolved := setPruneTestRequest(t, ctx, keeper, "calldata", true)
+	open := setPruneTestRequest(t, ctx, keeper, "calldata", false)
+	after := setPruneTestRequest(t, ctx, keeper, "calldata", true)
+
+	// The open request expires at height 11, and blocks pruning of later requests until then.
+	if pruned := keeper.PruneRequests(ctx.WithBlockHeight(5)); pruned != 1 {
+		t.Errorf("pruned %d requests, expected only the one before the open request", pruned)
+	}
+	if !isPruned(t, ctx, keeper, resolved) || isPruned(t, ctx, keeper, open) || isPruned(t, ctx, keeper, after) {
+		t.Error("expected pruning to stop at the open request")
+	}
+	if pruned := keeper.PruneRequests(ctx.WithBlockHeight(12)); pruned != 2 {
+		t.Errorf("pruned %d requests after the open request expired, expected 2", pruned)
+	}
+}
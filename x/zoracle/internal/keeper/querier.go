@@ -1,6 +1,7 @@
 package keeper
 
 import (
+	"encoding/hex"
 	"fmt"
 	"strconv"
 
@@ -32,6 +33,20 @@ func NewQuerier(keeper Keeper) sdk.Querier {
 			return queryPending(ctx, path[1:], req, keeper)
 		case types.QueryRequestNumber:
 			return queryRequestNumber(ctx, req, keeper)
+		case types.QueryParams:
+			return queryParams(ctx, req, keeper)
+		case types.QueryLatestResult:
+			return queryLatestResult(ctx, path[1:], req, keeper)
+		case types.QueryReferenceData:
+			return queryReferenceData(ctx, path[1:], req, keeper)
+		case types.QuerySubscriptionByID:
+			return querySubscriptionByID(ctx, path[1:], req, keeper)
+		case types.QueryRequestsByRequester:
+			return queryRequestsByRequester(ctx, path[1:], req, keeper)
+		case types.QuerySearchRequests:
+			return querySearchRequests(ctx, req, keeper)
+		case types.QueryPendingAssignments:
+			return queryPendingAssignments(ctx, path[1:], req, keeper)
 		default:
 			return nil, sdkerrors.Wrapf(
 				sdkerrors.ErrUnknownRequest,
@@ -86,12 +101,18 @@ func buildRequestQuerierInfo(
 		}
 	}
 
+	var reportCommitment *types.ReportCommitment
+	if commitment, err := keeper.GetReportCommitment(ctx, id); err == nil {
+		reportCommitment = &commitment
+	}
+
 	return types.NewRequestQuerierInfo(
 		id,
 		request,
 		rawRequests,
 		reports,
 		result,
+		reportCommitment,
 	), nil
 }
 
@@ -237,6 +258,8 @@ func queryRequestByID(
 	return codec.MustMarshalJSONIndent(keeper.cdc, request), nil
 }
 
+// queryRequests is a query function to get the requests in a range of request IDs. IDs without a
+// request in the store are skipped.
 func queryRequests(
 	ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper,
 ) ([]byte, error) {
@@ -271,11 +294,195 @@ func queryRequests(
 	return codec.MustMarshalJSONIndent(keeper.cdc, requests), nil
 }
 
-// queryPending is a query function to get the list of request IDs that are still on pending status.
+// queryRequestsByRequester is a query function to get the requests made by a requester, starting
+// from the given request ID.
+func queryRequestsByRequester(
+	ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper,
+) ([]byte, error) {
+	if len(path) != 3 {
+		return nil, fmt.Errorf("must specify the requester, request start id and number of requests")
+	}
+	requester, err := sdk.AccAddressFromBech32(path[0])
+	if err != nil {
+		return nil, fmt.Errorf(fmt.Sprintf("wrong format for requester %s", err.Error()))
+	}
+
+	startID, err := strconv.ParseInt(path[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf(fmt.Sprintf("wrong format for request start id %s", err.Error()))
+	}
+	if startID < 1 {
+		return nil, fmt.Errorf("request start id should be >= 1")
+	}
+
+	numberOfRequests, err := strconv.ParseInt(path[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf(fmt.Sprintf("wrong format for number of requests %s", err.Error()))
+	}
+	if numberOfRequests < 1 || numberOfRequests > 100 {
+		return nil, fmt.Errorf("number of requests should be >= 1 and <= 100")
+	}
+
+	requests := make([]types.RequestQuerierInfo, 0)
+	for _, id := range keeper.GetRequestIDsByRequester(ctx, requester, types.RequestID(startID), int(numberOfRequests)) {
+		request, err := buildRequestQuerierInfo(ctx, keeper, id)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	return codec.MustMarshalJSONIndent(keeper.cdc, requests), nil
+}
+
+// querySearchRequests is a query function to get a page of the requests that match the filters
+// given in the query data.
+func querySearchRequests(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	var params types.QueryRequestsParams
+	if err := keeper.cdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+	}
+	if err := params.Validate(); err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, err.Error())
+	}
+
+	ids, nextID := keeper.SearchRequests(ctx, params)
+	requests := make([]types.RequestQuerierInfo, 0, len(ids))
+	for _, id := range ids {
+		request, err := buildRequestQuerierInfo(ctx, keeper, id)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	return codec.MustMarshalJSONIndent(keeper.cdc, types.NewRequestSearchResult(requests, nextID)), nil
+}
+
+// queryPendingAssignments is a query function to get the open requests that a validator has yet to
+// report to, starting from the given request ID, with the raw data requests and data sources it
+// needs to report on them.
+func queryPendingAssignments(
+	ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper,
+) ([]byte, error) {
+	if len(path) != 3 {
+		return nil, fmt.Errorf("must specify the validator address, request start id and number of requests")
+	}
+	validator, err := sdk.ValAddressFromBech32(path[0])
+	if err != nil {
+		return nil, fmt.Errorf(fmt.Sprintf("wrong format for validator address %s", err.Error()))
+	}
+
+	startID, err := strconv.ParseInt(path[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf(fmt.Sprintf("wrong format for request start id %s", err.Error()))
+	}
+
+	numberOfRequests, err := strconv.ParseInt(path[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf(fmt.Sprintf("wrong format for number of requests %s", err.Error()))
+	}
+	if numberOfRequests < 1 || numberOfRequests > 100 {
+		return nil, fmt.Errorf("number of requests should be >= 1 and <= 100")
+	}
+
+	assignments := make([]types.PendingAssignmentQuerierInfo, 0)
+	for _, id := range keeper.GetPendingAssignments(ctx, validator, types.RequestID(startID), int(numberOfRequests)) {
+		request, err := keeper.GetRequest(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		rawRequests := keeper.GetRawDataRequestWithExternalIDs(ctx, id)
+		dataSourceIDs := make([]types.DataSourceID, 0)
+		seen := make(map[types.DataSourceID]bool)
+		for _, rawRequest := range rawRequests {
+			dataSourceID := rawRequest.RawDataRequest.DataSourceID
+			if !seen[dataSourceID] {
+				seen[dataSourceID] = true
+				dataSourceIDs = append(dataSourceIDs, dataSourceID)
+			}
+		}
+
+		assignments = append(assignments, types.NewPendingAssignmentQuerierInfo(
+			id,
+			request.OracleScriptID,
+			request.Calldata,
+			request.RequestHeight,
+			request.ExpirationHeight,
+			rawRequests,
+			dataSourceIDs,
+		))
+	}
+	return codec.MustMarshalJSONIndent(keeper.cdc, assignments), nil
+}
+
+// queryPending is a query function to get the list of request IDs that are still on pending status,
+// in the order they will be served by EndBlock.
 func queryPending(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
-	return codec.MustMarshalJSONIndent(keeper.cdc, keeper.GetPendingResolveList(ctx)), nil
+	return codec.MustMarshalJSONIndent(keeper.cdc, keeper.GetPendingRequestsInScheduleOrder(ctx)), nil
 }
 
 func queryRequestNumber(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
 	return codec.MustMarshalJSONIndent(keeper.cdc, keeper.GetRequestCount(ctx)), nil
 }
+
+// queryParams is a query function to get the current parameters of the zoracle module.
+func queryParams(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	return codec.MustMarshalJSONIndent(keeper.cdc, keeper.GetParams(ctx)), nil
+}
+
+// queryLatestResult is a query function to get the latest result of an oracle script and calldata.
+// The path holds the oracle script ID followed by the hex encoded calldata, which may be omitted
+// when empty.
+func queryLatestResult(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	if len(path) != 1 && len(path) != 2 {
+		return nil, fmt.Errorf("must specify the oracle script id and calldata")
+	}
+	id, err := strconv.ParseInt(path[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf(fmt.Sprintf("wrong format for oracle script id %s", err.Error()))
+	}
+	var calldata []byte
+	if len(path) == 2 {
+		calldata, err = hex.DecodeString(path[1])
+		if err != nil {
+			return nil, fmt.Errorf(fmt.Sprintf("wrong format for calldata %s", err.Error()))
+		}
+	}
+
+	oracleScriptID := types.OracleScriptID(id)
+	requestID, err := keeper.GetLatestResultRequestID(ctx, oracleScriptID, calldata)
+	if err != nil {
+		return nil, err
+	}
+	result, err := keeper.GetResult(ctx, requestID, oracleScriptID, calldata)
+	if err != nil {
+		return nil, err
+	}
+	return codec.MustMarshalJSONIndent(keeper.cdc, types.NewLatestResultQuerierInfo(requestID, result)), nil
+}
+
+func queryReferenceData(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	if len(path) != 2 {
+		return nil, fmt.Errorf("must specify the base and quote currencies")
+	}
+	data, err := keeper.GetReferenceData(ctx, path[0], path[1])
+	if err != nil {
+		return nil, err
+	}
+	return codec.MustMarshalJSONIndent(keeper.cdc, data), nil
+}
+
+func querySubscriptionByID(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("must specify the subscription id")
+	}
+	intID, err := strconv.ParseInt(path[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf(fmt.Sprintf("wrong format for subscription id %s", err.Error()))
+	}
+	subscription, err := keeper.GetSubscription(ctx, types.SubscriptionID(intID))
+	if err != nil {
+		return nil, err
+	}
+	return codec.MustMarshalJSONIndent(keeper.cdc, subscription), nil
+}
@@ -80,9 +80,51 @@ func (k Keeper) AddNewRawDataRequest(
 		"RawDataRequest",
 	)
 	k.SetRawDataRequest(ctx, requestID, externalID, types.NewRawDataRequest(dataSourceID, calldata))
+	k.addRawDataRequestExternalID(ctx, requestID, externalID)
 	return k.ValidateDataSourceCount(ctx, requestID)
 }
 
+// addRawDataRequestExternalID increments the raw data request count of the given request and
+// indexes the external ID under its own key.
+func (k Keeper) addRawDataRequestExternalID(ctx sdk.Context, requestID types.RequestID, externalID types.ExternalID) {
+	store := ctx.KVStore(k.storeKey)
+	count := k.GetRawDataRequestCount(ctx, requestID)
+	store.Set(types.RawDataRequestCountStoreKey(requestID), k.cdc.MustMarshalBinaryLengthPrefixed(count+1))
+	store.Set(types.RawDataRequestExternalIDStoreKey(requestID, externalID), []byte{})
+}
+
+// MigrateRawDataRequestCounts fills in the raw data request count and the external ID index of
+// every request stored before they were indexed. Requests that already have a count are left
+// untouched.
+func (k Keeper) MigrateRawDataRequestCounts(ctx sdk.Context) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.RawDataRequestStoreKeyPrefix)
+	defer iterator.Close()
+
+	var requestIDs []types.RequestID
+	externalIDsOf := make(map[types.RequestID][]types.ExternalID)
+	for ; iterator.Valid(); iterator.Next() {
+		requestID := types.GetRequestIDFromRawDataRequestKey(iterator.Key())
+		if store.Has(types.RawDataRequestCountStoreKey(requestID)) {
+			continue
+		}
+		if _, ok := externalIDsOf[requestID]; !ok {
+			requestIDs = append(requestIDs, requestID)
+		}
+		externalIDsOf[requestID] = append(
+			externalIDsOf[requestID], types.GetExternalIDFromRawDataRequestKey(iterator.Key()),
+		)
+	}
+
+	for _, requestID := range requestIDs {
+		externalIDs := externalIDsOf[requestID]
+		store.Set(types.RawDataRequestCountStoreKey(requestID), k.cdc.MustMarshalBinaryLengthPrefixed(int64(len(externalIDs))))
+		for _, externalID := range externalIDs {
+			store.Set(types.RawDataRequestExternalIDStoreKey(requestID, externalID), []byte{})
+		}
+	}
+}
+
 // GetRawDataRequestIterator is a function to get iterator on all raw data request that belong to
 // given request id
 func (k Keeper) GetRawDataRequestIterator(ctx sdk.Context, requestID types.RequestID) sdk.Iterator {
@@ -93,12 +135,28 @@ func (k Keeper) GetRawDataRequestIterator(ctx sdk.Context, requestID types.Reque
 
 // GetRawDataRequestCount returns amount of raw data requests in given request.
 func (k Keeper) GetRawDataRequestCount(ctx sdk.Context, requestID types.RequestID) int64 {
-	iterator := k.GetRawDataRequestIterator(ctx, requestID)
-	count := 0
+	var count int64
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.RawDataRequestCountStoreKey(requestID))
+	if bz == nil {
+		return 0
+	}
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &count)
+	return count
+}
+
+// GetRawDataRequestExternalIDs returns the external IDs of raw data requests in given request,
+// sorted in ascending order.
+func (k Keeper) GetRawDataRequestExternalIDs(ctx sdk.Context, requestID types.RequestID) []types.ExternalID {
+	prefix := types.GetIteratorPrefix(types.RawDataRequestExternalIDStoreKeyPrefix, requestID)
+	iterator := sdk.KVStorePrefixIterator(ctx.KVStore(k.storeKey), prefix)
+	defer iterator.Close()
+
+	externalIDs := []types.ExternalID{}
 	for ; iterator.Valid(); iterator.Next() {
-		count++
+		externalIDs = append(externalIDs, types.GetExternalIDFromRawDataRequestExternalIDKey(iterator.Key()))
 	}
-	return int64(count)
+	return externalIDs
 }
 
 // GetRawDataRequests returns a list of raw data requests in given request.
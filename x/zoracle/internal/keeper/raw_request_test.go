@@ -0,0 +1,163 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+// setRawRequestTestRequest stores an open request to the given validators, all of which must
+// report, and a data source for its raw data requests.
+func setRawRequestTestRequest(
+	ctx sdk.Context, keeper Keeper, requestID types.RequestID, validators []sdk.ValAddress,
+) {
+	owner := sdk.AccAddress([]byte("owner_______________"))
+	keeper.SetDataSource(ctx, 1, types.NewDataSource(owner, "source", "description", sdk.Coins{}, []byte("executable")))
+	keeper.SetRequest(ctx, requestID, types.NewRequest(
+		1, []byte("calldata"), validators, int64(len(validators)), ctx.BlockHeight(), 0, ctx.BlockHeight()+100,
+		1000, "", "", 1, sdk.Coins{}, owner,
+	))
+}
+
+// testReports returns a report with the given external IDs.
+func testReports(externalIDs ...types.ExternalID) []types.RawDataReportWithID {
+	reports := make([]types.RawDataReportWithID, len(externalIDs))
+	for i, externalID := range externalIDs {
+		reports[i] = types.NewRawDataReportWithID(externalID, 0, []byte("data"))
+	}
+	return reports
+}
+
+func TestGetRawDataRequestExternalIDsSorted(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	setRawRequestTestRequest(ctx, keeper, 1, []sdk.ValAddress{sdk.ValAddress([]byte("validator"))})
+	for _, externalID := range []types.ExternalID{7, -5, 3, 0, -1} {
+		if err := keeper.AddNewRawDataRequest(ctx, 1, externalID, 1, []byte("calldata")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	externalIDs := keeper.GetRawDataRequestExternalIDs(ctx, 1)
+	expected := []types.ExternalID{-5, -1, 0, 3, 7}
+	if len(externalIDs) != len(expected) {
+		t.Fatalf("external IDs %v, expected %v", externalIDs, expected)
+	}
+	for i := range expected {
+		if externalIDs[i] != expected[i] {
+			t.Fatalf("external IDs %v, expected %v", externalIDs, expected)
+		}
+	}
+	if count := keeper.GetRawDataRequestCount(ctx, 1); count != 5 {
+		t.Errorf("raw data request count %d, expected 5", count)
+	}
+}
+
+func TestAddReportMatchesExternalIDs(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	validator := sdk.ValAddress([]byte("validator"))
+	setRawRequestTestRequest(ctx, keeper, 1, []sdk.ValAddress{validator})
+	for _, externalID := range []types.ExternalID{3, -5, 7} {
+		if err := keeper.AddNewRawDataRequest(ctx, 1, externalID, 1, []byte("calldata")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reporter := sdk.AccAddress(validator)
+	invalid := map[string][]types.RawDataReportWithID{
+		"missing report":   testReports(-5, 3),
+		"extra report":     testReports(-5, 3, 7, 8),
+		"unknown ID":       testReports(-5, 3, 8),
+		"decreasing order": testReports(-5, 7, 3),
+		"duplicate ID":     testReports(-5, 3, 3),
+		"negative ID last": testReports(3, 7, -5),
+	}
+	for name, reports := range invalid {
+		if err := keeper.AddReport(ctx, 1, reports, validator, reporter); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	if err := keeper.AddReport(ctx, 1, testReports(-5, 3, 7), validator, reporter); err != nil {
+		t.Fatal(err)
+	}
+	for _, externalID := range []types.ExternalID{-5, 3, 7} {
+		if _, err := keeper.GetRawDataReport(ctx, 1, externalID, validator); err != nil {
+			t.Errorf("external ID %d: %v", externalID, err)
+		}
+	}
+}
+
+func TestMigrateRawDataRequestCounts(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	validators := []sdk.ValAddress{sdk.ValAddress([]byte("validator"))}
+	// Requests 1 and 2 were stored before raw data requests were indexed.
+	for requestID, externalIDs := range map[types.RequestID][]types.ExternalID{1: {2, -1, 1}, 2: {4}} {
+		setRawRequestTestRequest(ctx, keeper, requestID, validators)
+		for _, externalID := range externalIDs {
+			keeper.SetRawDataRequest(ctx, requestID, externalID, types.NewRawDataRequest(1, []byte("calldata")))
+		}
+	}
+	// Request 3 is already indexed.
+	setRawRequestTestRequest(ctx, keeper, 3, validators)
+	if err := keeper.AddNewRawDataRequest(ctx, 3, 5, 1, []byte("calldata")); err != nil {
+		t.Fatal(err)
+	}
+
+	keeper.MigrateRawDataRequestCounts(ctx)
+	keeper.MigrateRawDataRequestCounts(ctx)
+
+	expected := map[types.RequestID][]types.ExternalID{1: {-1, 1, 2}, 2: {4}, 3: {5}}
+	for requestID, expectedIDs := range expected {
+		if count := keeper.GetRawDataRequestCount(ctx, requestID); count != int64(len(expectedIDs)) {
+			t.Errorf("request %d: raw data request count %d, expected %d", requestID, count, len(expectedIDs))
+		}
+		externalIDs := keeper.GetRawDataRequestExternalIDs(ctx, requestID)
+		if len(externalIDs) != len(expectedIDs) {
+			t.Errorf("request %d: external IDs %v, expected %v", requestID, externalIDs, expectedIDs)
+			continue
+		}
+		for i := range expectedIDs {
+			if externalIDs[i] != expectedIDs[i] {
+				t.Errorf("request %d: external IDs %v, expected %v", requestID, externalIDs, expectedIDs)
+				break
+			}
+		}
+	}
+}
+
+// BenchmarkPrepareAndReportMaxDataSources measures adding MaxDataSourceCountPerRequest raw data
+// requests to a request, as the prepare phase of a script using every allowed data source does,
+// and then adding a report for all of them from each requested validator.
+func BenchmarkPrepareAndReportMaxDataSources(b *testing.B) {
+	ctx, keeper := CreateTestInput()
+	validators := []sdk.ValAddress{
+		sdk.ValAddress([]byte("validator1")),
+		sdk.ValAddress([]byte("validator2")),
+		sdk.ValAddress([]byte("validator3")),
+		sdk.ValAddress([]byte("validator4")),
+	}
+	dataSourceCount := keeper.MaxDataSourceCountPerRequest(ctx)
+	reports := make([]types.RawDataReportWithID, dataSourceCount)
+	for i := range reports {
+		reports[i] = types.NewRawDataReportWithID(types.ExternalID(i+1), 0, []byte("data"))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		requestID := types.RequestID(i + 1)
+		setRawRequestTestRequest(ctx, keeper, requestID, validators)
+		// Add the external IDs in descending order, the reverse of the order they are read in.
+		for externalID := dataSourceCount; externalID > 0; externalID-- {
+			err := keeper.AddNewRawDataRequest(ctx, requestID, types.ExternalID(externalID), 1, []byte("calldata"))
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+		for _, validator := range validators {
+			if err := keeper.AddReport(ctx, requestID, reports, validator, sdk.AccAddress(validator)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
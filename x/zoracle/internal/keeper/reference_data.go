@@ -0,0 +1,95 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+// GetReferenceDataAccount returns the address of the module account that pays for reference
+// symbol requests.
+func (k Keeper) GetReferenceDataAccount() sdk.AccAddress {
+	return k.SupplyKeeper.GetModuleAddress(types.ReferenceDataAccountName)
+}
+
+// GetReferenceSymbolLastRequestHeight returns the block height at which a request was last made
+// for the given reference symbol, and false if none was ever made.
+func (k Keeper) GetReferenceSymbolLastRequestHeight(ctx sdk.Context, symbol string) (int64, bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.ReferenceSymbolStoreKey(symbol))
+	if bz == nil {
+		return 0, false
+	}
+	var height int64
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &height)
+	return height, true
+}
+
+// SetReferenceSymbolLastRequestHeight saves the block height at which a request was last made for
+// the given reference symbol.
+func (k Keeper) SetReferenceSymbolLastRequestHeight(ctx sdk.Context, symbol string, height int64) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.ReferenceSymbolStoreKey(symbol), k.cdc.MustMarshalBinaryLengthPrefixed(height))
+}
+
+// GetDueReferenceSymbols returns the reference symbols whose refresh interval has passed since
+// their last request, in the order they are registered.
+func (k Keeper) GetDueReferenceSymbols(ctx sdk.Context) []types.ReferenceSymbol {
+	var due []types.ReferenceSymbol
+	for _, symbol := range k.ReferenceSymbols(ctx) {
+		lastHeight, ok := k.GetReferenceSymbolLastRequestHeight(ctx, symbol.Symbol)
+		if !ok || ctx.BlockHeight()-lastHeight >= symbol.RefreshInterval {
+			due = append(due, symbol)
+		}
+	}
+	return due
+}
+
+// GetReferenceData returns the latest rate of the base currency in terms of the quote currency.
+// If only the inverse pair is registered, its rate is inverted.
+func (k Keeper) GetReferenceData(ctx sdk.Context, base, quote string) (types.ReferenceData, error) {
+	symbols := k.ReferenceSymbols(ctx)
+	if symbol, ok := symbols.Find(types.ReferenceSymbolName(base, quote)); ok {
+		return k.getReferenceSymbolData(ctx, symbol)
+	}
+	if symbol, ok := symbols.Find(types.ReferenceSymbolName(quote, base)); ok {
+		data, err := k.getReferenceSymbolData(ctx, symbol)
+		if err != nil {
+			return types.ReferenceData{}, err
+		}
+		if data.Rate.IsZero() {
+			return types.ReferenceData{}, sdkerrors.Wrapf(types.ErrBadDataValue,
+				"GetReferenceData: Cannot invert the zero rate of %s.",
+				symbol.Symbol,
+			)
+		}
+		data.Rate = sdk.OneDec().Quo(data.Rate)
+		return data, nil
+	}
+	return types.ReferenceData{}, sdkerrors.Wrapf(types.ErrItemNotFound,
+		"GetReferenceData: Unknown reference symbol %s.",
+		types.ReferenceSymbolName(base, quote),
+	)
+}
+
+// getReferenceSymbolData decodes the latest result of the oracle script and calldata of the
+// given reference symbol.
+func (k Keeper) getReferenceSymbolData(ctx sdk.Context, symbol types.ReferenceSymbol) (types.ReferenceData, error) {
+	requestID, err := k.GetLatestResultRequestID(ctx, symbol.OracleScriptID, symbol.Calldata)
+	if err != nil {
+		return types.ReferenceData{}, err
+	}
+	result, err := k.GetResult(ctx, requestID, symbol.OracleScriptID, symbol.Calldata)
+	if err != nil {
+		return types.ReferenceData{}, err
+	}
+	rate, err := types.DecodeReferenceRate(result.Data, symbol.Multiplier)
+	if err != nil {
+		return types.ReferenceData{}, sdkerrors.Wrapf(types.ErrBadDataValue,
+			"getReferenceSymbolData: Bad result of %s: %s.",
+			symbol.Symbol,
+			err.Error(),
+		)
+	}
+	return types.NewReferenceData(rate, result.AggregationTime, requestID), nil
+}
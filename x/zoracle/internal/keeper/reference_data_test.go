@@ -0,0 +1,119 @@
+package keeper
+
+import (
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+// setReferenceDataTestResult resolves a new request for the oracle script and calldata of the
+// given reference symbol with the given result.
+func setReferenceDataTestResult(
+	t *testing.T, ctx sdk.Context, keeper Keeper, symbol types.ReferenceSymbol, result []byte,
+) types.RequestID {
+	id := setTestRequest(ctx, keeper, 1000, sdk.Coins{})
+	if err := keeper.AddResult(ctx, id, symbol.OracleScriptID, symbol.Calldata, result); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func encodeReferenceRate(rate uint64) []byte {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, rate)
+	return data
+}
+
+func TestGetReferenceData(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	btc := types.NewReferenceSymbol("BTC/USD", 1, []byte("BTC"), 1, 1, 100, 1000, 1000, 10, 100)
+	eth := types.NewReferenceSymbol("ETH/USD", 1, []byte("ETH"), 1, 1, 100, 1000, 1000, 10, 100)
+	keeper.SetReferenceSymbols(ctx, types.ReferenceSymbols{btc, eth})
+	id := setReferenceDataTestResult(t, ctx, keeper, btc, encodeReferenceRate(1234567))
+
+	data, err := keeper.GetReferenceData(ctx, "BTC", "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := types.NewReferenceData(sdk.NewDecWithPrec(1234567, 2), ctx.BlockTime().Unix(), id)
+	if !data.Rate.Equal(expected.Rate) || data.LastUpdated != expected.LastUpdated || data.RequestID != id {
+		t.Errorf("reference data %+v, expected %+v", data, expected)
+	}
+
+	// Only BTC/USD is registered, so USD/BTC is its inverse.
+	inverted, err := keeper.GetReferenceData(ctx, "USD", "BTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate := sdk.OneDec().Quo(expected.Rate); !inverted.Rate.Equal(rate) || inverted.RequestID != id {
+		t.Errorf("inverted reference data %+v, expected rate %s of request %d", inverted, rate, id)
+	}
+
+	if _, err := keeper.GetReferenceData(ctx, "BTC", "EUR"); err == nil {
+		t.Error("expected an unknown pair to be rejected")
+	}
+	if _, err := keeper.GetReferenceData(ctx, "ETH", "USD"); err == nil {
+		t.Error("expected a pair without a result to be rejected")
+	}
+}
+
+func TestGetReferenceDataBadResult(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	btc := types.NewReferenceSymbol("BTC/USD", 1, []byte("BTC"), 1, 1, 100, 1000, 1000, 10, 100)
+	keeper.SetReferenceSymbols(ctx, types.ReferenceSymbols{btc})
+
+	setReferenceDataTestResult(t, ctx, keeper, btc, []byte("short"))
+	if _, err := keeper.GetReferenceData(ctx, "BTC", "USD"); err == nil {
+		t.Error("expected a result that is not 8 bytes to be rejected")
+	}
+
+	// A zero rate is a valid result but cannot be inverted.
+	setReferenceDataTestResult(t, ctx, keeper, btc, encodeReferenceRate(0))
+	if data, err := keeper.GetReferenceData(ctx, "BTC", "USD"); err != nil || !data.Rate.IsZero() {
+		t.Errorf("reference data %+v with error %v, expected a zero rate", data, err)
+	}
+	if _, err := keeper.GetReferenceData(ctx, "USD", "BTC"); err == nil {
+		t.Error("expected inverting a zero rate to be rejected")
+	}
+}
+
+func TestGetDueReferenceSymbols(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	btc := types.NewReferenceSymbol("BTC/USD", 1, []byte("BTC"), 1, 1, 100, 1000, 1000, 10, 100)
+	eth := types.NewReferenceSymbol("ETH/USD", 1, []byte("ETH"), 1, 1, 100, 1000, 1000, 5, 100)
+	keeper.SetReferenceSymbols(ctx, types.ReferenceSymbols{btc, eth})
+	keeper.SetReferenceSymbolLastRequestHeight(ctx, btc.Symbol, 1)
+	keeper.SetReferenceSymbolLastRequestHeight(ctx, eth.Symbol, 1)
+
+	cases := []struct {
+		height   int64
+		expected []string
+	}{
+		{5, nil},
+		{6, []string{"ETH/USD"}},
+		{11, []string{"BTC/USD", "ETH/USD"}},
+	}
+	for _, c := range cases {
+		var due []string
+		for _, symbol := range keeper.GetDueReferenceSymbols(ctx.WithBlockHeight(c.height)) {
+			due = append(due, symbol.Symbol)
+		}
+		if fmt.Sprint(due) != fmt.Sprint(c.expected) {
+			t.Errorf("height %d: due symbols %v, expected %v", c.height, due, c.expected)
+		}
+	}
+
+	// A symbol that was never requested is due right away.
+	if due := keeper.GetDueReferenceSymbols(ctx); len(due) != 0 {
+		t.Errorf("due symbols %v, expected none", due)
+	}
+	keeper.SetReferenceSymbols(ctx, types.ReferenceSymbols{btc, eth, types.NewReferenceSymbol(
+		"ATOM/USD", 1, []byte("ATOM"), 1, 1, 100, 1000, 1000, 10, 100,
+	)})
+	if due := keeper.GetDueReferenceSymbols(ctx); len(due) != 1 || due[0].Symbol != "ATOM/USD" {
+		t.Errorf("due symbols %v, expected only ATOM/USD", due)
+	}
+}
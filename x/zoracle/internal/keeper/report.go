@@ -1,6 +1,9 @@
 package keeper
 
 import (
+	"bytes"
+	"sort"
+
 	"github.com/cosmos/gaia/x/zoracle/internal/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
@@ -69,8 +72,9 @@ func (k Keeper) AddReport(
 		}
 	}
 
-	rawDataRequestCount := k.GetRawDataRequestCount(ctx, requestID)
-	if int64(len(dataSet)) != rawDataRequestCount {
+	externalIDs := k.GetRawDataRequestExternalIDs(ctx, requestID)
+	rawDataRequestCount := len(externalIDs)
+	if len(dataSet) != rawDataRequestCount {
 		return sdkerrors.Wrapf(types.ErrBadDataValue,
 			"AddReport: Request ID %d: Expects %d raw data reports, but received %d raw data reports.",
 			requestID,
@@ -84,7 +88,9 @@ func (k Keeper) AddReport(
 		if idx != 0 && lastExternalID >= rawReport.ExternalDataID {
 			return sdkerrors.Wrapf(types.ErrBadDataValue, "AddReport: Raw data reports are not in an incresaing order.")
 		}
-		if !k.CheckRawDataRequestExists(ctx, requestID, rawReport.ExternalDataID) {
+		// Reports are strictly increasing and as many as the sorted external IDs, so they must
+		// match one to one.
+		if rawReport.ExternalDataID != externalIDs[idx] {
 			return sdkerrors.Wrapf(types.ErrBadDataValue,
 				"AddReport: RequestID %d: Unknown external ID %d",
 				requestID,
@@ -113,6 +119,7 @@ func (k Keeper) AddReport(
 
 	request.ReceivedValidators = append(request.ReceivedValidators, validator)
 	k.SetRequest(ctx, requestID, request)
+	k.deleteAssignment(ctx, validator, requestID)
 	if k.ShouldBecomePendingResolve(ctx, requestID) {
 		err := k.AddPendingRequest(ctx, requestID)
 		if err != nil {
@@ -164,3 +171,63 @@ func (k Keeper) GetRawDataReportsIterator(ctx sdk.Context, requestID types.Reque
 	store := ctx.KVStore(k.storeKey)
 	return sdk.KVStorePrefixIterator(store, prefix)
 }
+
+// CleanupReports deletes the raw data reports of a resolved request according to the
+// ReportCleanupMode parameter. In commit mode, the reports are replaced by a ReportCommitment.
+func (k Keeper) CleanupReports(ctx sdk.Context, requestID types.RequestID) {
+	mode := k.ReportCleanupMode(ctx)
+	if mode == types.ReportCleanupKeep {
+		return
+	}
+
+	var keys [][]byte
+	var reportHashes []types.ReportHash
+	iterator := k.GetRawDataReportsIterator(ctx, requestID)
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, iterator.Key())
+		if mode == types.ReportCleanupCommit {
+			validator, externalID := types.GetValidatorAddressAndExternalID(iterator.Key(), requestID)
+			var rawReport types.RawDataReport
+			k.cdc.MustUnmarshalBinaryBare(iterator.Value(), &rawReport)
+			reportHashes = append(reportHashes, types.ReportHash{
+				Validator:  validator,
+				ExternalID: externalID,
+				Hash:       types.HashRawDataReport(validator, externalID, rawReport),
+			})
+		}
+	}
+	iterator.Close()
+
+	store := ctx.KVStore(k.storeKey)
+	for _, key := range keys {
+		store.Delete(key)
+	}
+	if len(reportHashes) != 0 {
+		// Keys order negative external IDs last, so sort the hashes into the documented order.
+		sort.Slice(reportHashes, func(i, j int) bool {
+			if reportHashes[i].ExternalID != reportHashes[j].ExternalID {
+				return reportHashes[i].ExternalID < reportHashes[j].ExternalID
+			}
+			return bytes.Compare(reportHashes[i].Validator, reportHashes[j].Validator) < 0
+		})
+		store.Set(
+			types.ReportCommitmentStoreKey(requestID),
+			k.cdc.MustMarshalBinaryBare(types.NewReportCommitment(reportHashes)),
+		)
+	}
+}
+
+// GetReportCommitment returns the commitment that replaced the raw data reports of a request.
+func (k Keeper) GetReportCommitment(ctx sdk.Context, requestID types.RequestID) (types.ReportCommitment, error) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.ReportCommitmentStoreKey(requestID))
+	if bz == nil {
+		return types.ReportCommitment{}, sdkerrors.Wrapf(types.ErrItemNotFound,
+			"GetReportCommitment: No report commitment for request ID %d.",
+			requestID,
+		)
+	}
+	var commitment types.ReportCommitment
+	k.cdc.MustUnmarshalBinaryBare(bz, &commitment)
+	return commitment, nil
+}
@@ -0,0 +1,132 @@
+package keeper
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+// setReportedTestRequest stores request 1 with raw data requests for external IDs -1 and 2, and
+// a report from each of its two validators.
+func setReportedTestRequest(t *testing.T, ctx sdk.Context, keeper Keeper) {
+	validators := []sdk.ValAddress{
+		sdk.ValAddress([]byte("validator2__________")),
+		sdk.ValAddress([]byte("validator1__________")),
+	}
+	setRawRequestTestRequest(ctx, keeper, 1, validators)
+	for _, externalID := range []types.ExternalID{2, -1} {
+		if err := keeper.AddNewRawDataRequest(ctx, 1, externalID, 1, []byte("calldata")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, validator := range validators {
+		reports := []types.RawDataReportWithID{
+			types.NewRawDataReportWithID(-1, uint8(i), []byte{byte(i)}),
+			types.NewRawDataReportWithID(2, 0, []byte("data")),
+		}
+		if err := keeper.AddReport(ctx, 1, reports, validator, sdk.AccAddress(validator)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// queryTestRequest returns request 1 as served by the querier.
+func queryTestRequest(t *testing.T, ctx sdk.Context, keeper Keeper) types.RequestQuerierInfo {
+	bz, err := NewQuerier(keeper)(ctx, []string{types.QueryRequestByID, "1"}, abci.RequestQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var info types.RequestQuerierInfo
+	keeper.cdc.MustUnmarshalJSON(bz, &info)
+	return info
+}
+
+func TestCleanupReportsKeep(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	setReportedTestRequest(t, ctx, keeper)
+	keeper.CleanupReports(ctx, 1)
+
+	info := queryTestRequest(t, ctx, keeper)
+	if len(info.Reports) != 2 || info.ReportCommitment != nil {
+		t.Errorf("got %d reports and commitment %v, expected the reports to be kept", len(info.Reports), info.ReportCommitment)
+	}
+}
+
+func TestCleanupReportsDelete(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	keeper.SetReportCleanupMode(ctx, types.ReportCleanupDelete)
+	setReportedTestRequest(t, ctx, keeper)
+	keeper.CleanupReports(ctx, 1)
+
+	iterator := keeper.GetRawDataReportsIterator(ctx, 1)
+	defer iterator.Close()
+	if iterator.Valid() {
+		t.Error("expected every raw data report to be deleted")
+	}
+	info := queryTestRequest(t, ctx, keeper)
+	if len(info.Reports) != 0 || info.ReportCommitment != nil {
+		t.Errorf("got %d reports and commitment %v, expected neither", len(info.Reports), info.ReportCommitment)
+	}
+	if len(info.RawDataRequests) != 2 {
+		t.Errorf("got %d raw data requests, expected them to be kept", len(info.RawDataRequests))
+	}
+}
+
+func TestCleanupReportsCommitMatchesServedReports(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	keeper.SetReportCleanupMode(ctx, types.ReportCleanupCommit)
+	setReportedTestRequest(t, ctx, keeper)
+
+	// Hash the reports the way a client holding the served reports would.
+	var expected []types.ReportHash
+	for _, report := range queryTestRequest(t, ctx, keeper).Reports {
+		for _, rawReport := range report.RawDataReports {
+			expected = append(expected, types.ReportHash{
+				Validator:  report.Validator,
+				ExternalID: rawReport.ExternalDataID,
+				Hash: types.HashRawDataReport(
+					report.Validator, rawReport.ExternalDataID,
+					types.NewRawDataReport(rawReport.ExitCode, rawReport.Data),
+				),
+			})
+		}
+	}
+	if len(expected) != 4 {
+		t.Fatalf("querier served %d reports, expected 4", len(expected))
+	}
+	sort.Slice(expected, func(i, j int) bool {
+		if expected[i].ExternalID != expected[j].ExternalID {
+			return expected[i].ExternalID < expected[j].ExternalID
+		}
+		return bytes.Compare(expected[i].Validator, expected[j].Validator) < 0
+	})
+
+	keeper.CleanupReports(ctx, 1)
+
+	info := queryTestRequest(t, ctx, keeper)
+	if len(info.Reports) != 0 {
+		t.Errorf("got %d reports, expected them to be replaced by the commitment", len(info.Reports))
+	}
+	if info.ReportCommitment == nil {
+		t.Fatal("expected the querier to serve the report commitment")
+	}
+	commitment := *info.ReportCommitment
+	if !bytes.Equal(commitment.Root, types.NewReportCommitment(expected).Root) {
+		t.Error("commitment root does not match the served reports")
+	}
+	if len(commitment.ReportHashes) != len(expected) {
+		t.Fatalf("got %d report hashes, expected %d", len(commitment.ReportHashes), len(expected))
+	}
+	for i := range expected {
+		got := commitment.ReportHashes[i]
+		if got.ExternalID != expected[i].ExternalID || !got.Validator.Equals(expected[i].Validator) ||
+			!bytes.Equal(got.Hash, expected[i].Hash) {
+			t.Errorf("report hash %d is %v, expected %v", i, got, expected[i])
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package keeper
 
 import (
+	"encoding/binary"
+	"sort"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	"github.com/cosmos/gaia/x/zoracle/internal/types"
@@ -29,7 +32,7 @@ func (k Keeper) GetRequest(ctx sdk.Context, id types.RequestID) (types.Request,
 func (k Keeper) AddRequest(
 	ctx sdk.Context, oracleScriptID types.OracleScriptID, calldata []byte,
 	requestedValidatorCount, sufficientValidatorCount, expiration int64, executeGas uint64,
-	sourcePort string, sourceChannel string,
+	sourcePort string, sourceChannel string, priorityFee sdk.Coins, requester sdk.AccAddress,
 ) (types.RequestID, error) {
 	if !k.CheckOracleScriptExists(ctx, oracleScriptID) {
 		return 0, sdkerrors.Wrapf(types.ErrItemNotFound,
@@ -69,8 +72,12 @@ func (k Keeper) AddRequest(
 		)
 	}
 
+	// Remember the gas schedule in force now, so execution is charged the same costs even if
+	// the schedule changes before the request gets resolved.
+	gasScheduleVersion := k.GetCurrentGasScheduleVersion(ctx)
+
 	requestID := k.GetNextRequestID(ctx)
-	k.SetRequest(ctx, requestID, types.NewRequest(
+	request := types.NewRequest(
 		oracleScriptID,
 		calldata,
 		validators,
@@ -81,7 +88,12 @@ func (k Keeper) AddRequest(
 		executeGas,
 		sourcePort,
 		sourceChannel,
-	))
+		gasScheduleVersion,
+		priorityFee,
+		requester,
+	)
+	k.SetRequest(ctx, requestID, request)
+	k.indexRequest(ctx, requestID, request)
 
 	return requestID, nil
 }
@@ -127,13 +139,122 @@ func (k Keeper) PayDataSourceFees(ctx sdk.Context, id types.RequestID, sender sd
 	return nil
 }
 
-func (k Keeper) SetResolve(ctx sdk.Context, id types.RequestID, resolveStatus types.ResolveStatus) error {
+// EscrowPriorityFee moves the priority fee of a request from the sender to the module account,
+// where it stays until the request is resolved. The fee must be paid in the bond denomination,
+// since requests are scheduled by the bond denomination amount only.
+func (k Keeper) EscrowPriorityFee(ctx sdk.Context, sender sdk.AccAddress, priorityFee sdk.Coins) error {
+	if priorityFee.IsZero() {
+		return nil
+	}
+	bondDenom := k.StakingKeeper.BondDenom(ctx)
+	for _, coin := range priorityFee {
+		if coin.Denom != bondDenom {
+			return sdkerrors.Wrapf(types.ErrInvalidBasicMsg,
+				"EscrowPriorityFee: Priority fee denomination (%s) is not the bond denomination (%s).",
+				coin.Denom,
+				bondDenom,
+			)
+		}
+	}
+	return k.SupplyKeeper.SendCoinsFromAccountToModule(ctx, sender, types.ModuleName, priorityFee)
+}
+
+// PayPriorityFee pays the escrowed priority fee of the given request to the fee collector, which
+// distributes it to the validators.
+func (k Keeper) PayPriorityFee(ctx sdk.Context, id types.RequestID) error {
+	request, err := k.GetRequest(ctx, id)
+	if err != nil {
+		return err
+	}
+	if request.PriorityFee.IsZero() {
+		return nil
+	}
+	return k.SupplyKeeper.SendCoinsFromModuleToModule(ctx, types.ModuleName, k.feeCollectorName, request.PriorityFee)
+}
+
+// RefundPriorityFee returns the escrowed priority fee of the given request to its requester, and
+// sets the priority fee of the request to zero so that it can never be refunded twice.
+func (k Keeper) RefundPriorityFee(ctx sdk.Context, id types.RequestID) error {
+	request, err := k.GetRequest(ctx, id)
+	if err != nil {
+		return err
+	}
+	if request.PriorityFee.IsZero() {
+		return nil
+	}
+	err = k.SupplyKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, request.Requester, request.PriorityFee)
+	if err != nil {
+		return err
+	}
+	request.PriorityFee = sdk.Coins{}
+	k.SetRequest(ctx, id, request)
+	return nil
+}
+
+// CancelRequest cancels an open request on behalf of its requester and refunds its escrowed
+// priority fee. A request can only be cancelled before it receives sufficient reports, since
+// after that it is already queued for resolution, and before it expires, since its priority fee
+// is refunded on expiry.
+func (k Keeper) CancelRequest(ctx sdk.Context, id types.RequestID, sender sdk.AccAddress) error {
+	request, err := k.GetRequest(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !request.Requester.Equals(sender) {
+		return sdkerrors.Wrapf(types.ErrUnauthorizedPermission,
+			"CancelRequest: Sender (%s) is not the requester (%s).",
+			sender.String(),
+			request.Requester.String(),
+		)
+	}
+
+	if request.ResolveStatus != types.Open {
+		return sdkerrors.Wrapf(types.ErrInvalidState,
+			"CancelRequest: Request ID %d: Expect resolve status to be %d, but actual value is %d.",
+			id,
+			types.Open,
+			request.ResolveStatus,
+		)
+	}
+
+	if int64(len(request.ReceivedValidators)) >= request.SufficientValidatorCount {
+		return sdkerrors.Wrapf(types.ErrInvalidState,
+			"CancelRequest: Request ID %d already has sufficient reports (%d).",
+			id,
+			len(request.ReceivedValidators),
+		)
+	}
+
+	if request.Expired || request.Pruned || request.ExpirationHeight < ctx.BlockHeight() {
+		return sdkerrors.Wrapf(types.ErrInvalidState,
+			"CancelRequest: Request ID %d expired at height %d.",
+			id,
+			request.ExpirationHeight,
+		)
+	}
+
+	if err := k.RefundPriorityFee(ctx, id); err != nil {
+		return err
+	}
+	return k.SetResolve(ctx, id, types.Cancelled, types.FailureReasonNone)
+}
+
+func (k Keeper) SetResolve(
+	ctx sdk.Context, id types.RequestID, resolveStatus types.ResolveStatus, failureReason types.FailureReason,
+) error {
 	request, err := k.GetRequest(ctx, id)
 	if err != nil {
 		return err
 	}
 
+	k.reindexResolveStatus(ctx, id, request.ResolveStatus, resolveStatus)
+	if resolveStatus != types.Open {
+		k.deleteAssignments(ctx, id, request)
+		k.unscheduleExpiration(ctx, id, request)
+	}
 	request.ResolveStatus = resolveStatus
+	request.FailureReason = failureReason
 	k.SetRequest(ctx, id, request)
 	return nil
 }
@@ -155,44 +276,124 @@ func (k Keeper) ShouldBecomePendingResolve(ctx sdk.Context, id types.RequestID)
 	return int64(len(request.ReceivedValidators)) == request.SufficientValidatorCount
 }
 
-// AddPendingRequest checks and append new request id to list if id already existed in list, it will return error.
+// AddPendingRequest appends the request to the pending list, which will be resolved during the
+// EndBlock call. An error is returned if the request is already pending.
 func (k Keeper) AddPendingRequest(ctx sdk.Context, requestID types.RequestID) error {
-	pendingList := k.GetPendingResolveList(ctx)
-	for _, entry := range pendingList {
-		if requestID == entry {
-			return sdkerrors.Wrapf(types.ErrItemDuplication,
-				"AddPendingRequest: Request ID %d already exists in the pending list",
-				requestID,
-			)
-		}
+	if k.IsPendingRequest(ctx, requestID) {
+		return sdkerrors.Wrapf(types.ErrItemDuplication,
+			"AddPendingRequest: Request ID %d already exists in the pending list",
+			requestID,
+		)
 	}
-	pendingList = append(pendingList, requestID)
-	k.SetPendingResolveList(ctx, pendingList)
+	request, err := k.GetRequest(ctx, requestID)
+	if err != nil {
+		return err
+	}
+	request.PendingHeight = ctx.BlockHeight()
+	k.SetRequest(ctx, requestID, request)
+
+	k.appendPendingRequest(ctx, requestID)
 	return nil
 }
 
-// SetPendingResolveList saves the list of pending request that will be resolved at end block.
-func (k Keeper) SetPendingResolveList(ctx sdk.Context, reqIDs []types.RequestID) {
+// appendPendingRequest stores the request ID at the end of the pending list.
+func (k Keeper) appendPendingRequest(ctx sdk.Context, requestID types.RequestID) {
+	store := ctx.KVStore(k.storeKey)
+	sequence := k.GetNextPendingSequence(ctx)
+	store.Set(types.PendingRequestStoreKey(sequence), sdk.Uint64ToBigEndian(uint64(requestID)))
+	store.Set(types.PendingRequestIndexStoreKey(requestID), sdk.Uint64ToBigEndian(sequence))
+}
+
+// IsPendingRequest checks if the request is in the pending list.
+func (k Keeper) IsPendingRequest(ctx sdk.Context, requestID types.RequestID) bool {
+	store := ctx.KVStore(k.storeKey)
+	return store.Has(types.PendingRequestIndexStoreKey(requestID))
+}
+
+// RemovePendingRequest removes the request from the pending list. It does nothing if the request
+// is not pending.
+func (k Keeper) RemovePendingRequest(ctx sdk.Context, requestID types.RequestID) {
 	store := ctx.KVStore(k.storeKey)
-	encoded := k.cdc.MustMarshalBinaryBare(reqIDs)
-	if encoded == nil {
-		encoded = []byte{}
+	indexKey := types.PendingRequestIndexStoreKey(requestID)
+	bz := store.Get(indexKey)
+	if bz == nil {
+		return
 	}
-	store.Set(types.PendingResolveListStoreKey, encoded)
+	store.Delete(types.PendingRequestStoreKey(binary.BigEndian.Uint64(bz)))
+	store.Delete(indexKey)
 }
 
-// GetPendingResolveList returns the list of pending request.
+// GetPendingResolveList returns the list of pending request in the order they were added.
 func (k Keeper) GetPendingResolveList(ctx sdk.Context) []types.RequestID {
+	iterator := sdk.KVStorePrefixIterator(ctx.KVStore(k.storeKey), types.PendingRequestStoreKeyPrefix)
+	defer iterator.Close()
+
+	reqIDs := []types.RequestID{}
+	for ; iterator.Valid(); iterator.Next() {
+		reqIDs = append(reqIDs, types.RequestID(binary.BigEndian.Uint64(iterator.Value())))
+	}
+	return reqIDs
+}
+
+// MigratePendingResolveList moves the pending list stored as a single serialized slice under
+// PendingResolveListStoreKey to the indexed pending keys, keeping its order, and starts the wait
+// of the migrated requests at the current height. It does nothing if the store has already been
+// migrated.
+func (k Keeper) MigratePendingResolveList(ctx sdk.Context) {
 	store := ctx.KVStore(k.storeKey)
 	reqIDsBytes := store.Get(types.PendingResolveListStoreKey)
-
-	// If the state is empty
-	if len(reqIDsBytes) == 0 {
-		return []types.RequestID{}
+	if reqIDsBytes == nil {
+		return
 	}
 
 	var reqIDs []types.RequestID
-	k.cdc.MustUnmarshalBinaryBare(reqIDsBytes, &reqIDs)
+	if len(reqIDsBytes) != 0 {
+		k.cdc.MustUnmarshalBinaryBare(reqIDsBytes, &reqIDs)
+	}
+	for _, requestID := range reqIDs {
+		if k.IsPendingRequest(ctx, requestID) {
+			continue
+		}
+		if request, err := k.GetRequest(ctx, requestID); err == nil && request.PendingHeight == 0 {
+			request.PendingHeight = ctx.BlockHeight()
+			k.SetRequest(ctx, requestID, request)
+		}
+		k.appendPendingRequest(ctx, requestID)
+	}
+	store.Delete(types.PendingResolveListStoreKey)
+}
 
-	return reqIDs
+// GetPendingRequestsInScheduleOrder returns the pending request IDs in the order EndBlock serves
+// them. Requests pending for at least PendingAgingBlocks come first in FIFO order. The others
+// follow by priority fee per execute gas in the bond denom, highest first, with ties kept in
+// FIFO order.
+func (k Keeper) GetPendingRequestsInScheduleOrder(ctx sdk.Context) []types.RequestID {
+	agingBlocks := k.PendingAgingBlocks(ctx)
+	bondDenom := k.StakingKeeper.BondDenom(ctx)
+
+	var aged, fresh []types.RequestID
+	requests := make(map[types.RequestID]types.Request)
+	for _, requestID := range k.GetPendingResolveList(ctx) {
+		request, err := k.GetRequest(ctx, requestID)
+		// Unknown requests go first so EndBlock drops them right away.
+		if err != nil || request.PendingBlocks(ctx.BlockHeight()) >= agingBlocks {
+			aged = append(aged, requestID)
+			continue
+		}
+		requests[requestID] = request
+		fresh = append(fresh, requestID)
+	}
+
+	sort.SliceStable(fresh, func(i, j int) bool {
+		return hasHigherPriority(requests[fresh[i]], requests[fresh[j]], bondDenom)
+	})
+	return append(aged, fresh...)
+}
+
+// hasHigherPriority returns whether request a pays a higher priority fee per execute gas in the
+// given denom than request b. The fees are compared by cross multiplication to stay exact.
+func hasHigherPriority(a, b types.Request, denom string) bool {
+	left := a.PriorityFee.AmountOf(denom).Mul(sdk.NewIntFromUint64(b.ExecuteGas))
+	right := b.PriorityFee.AmountOf(denom).Mul(sdk.NewIntFromUint64(a.ExecuteGas))
+	return left.GT(right)
 }
@@ -0,0 +1,212 @@
+package keeper
+
+import (
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+// maxRequestSearchScan is the maximum number of requests that a single request search checks
+// against its filters, so that a search for rare requests cannot make a query arbitrarily slow.
+const maxRequestSearchScan = 10000
+
+// indexRequest adds a new request to the secondary indexes used to search requests, assigns it to
+// its requested validators and schedules its expiration.
+func (k Keeper) indexRequest(ctx sdk.Context, id types.RequestID, request types.Request) {
+	k.setRequestSearchIndexes(ctx, id, request)
+	store := ctx.KVStore(k.storeKey)
+	for _, validator := range request.RequestedValidators {
+		store.Set(types.ValidatorAssignmentStoreKey(validator, id), []byte{})
+	}
+	k.scheduleExpiration(ctx, id, request)
+}
+
+// setRequestSearchIndexes adds the request to the secondary indexes used to search requests, under
+// its current resolve status.
+func (k Keeper) setRequestSearchIndexes(ctx sdk.Context, id types.RequestID, request types.Request) {
+	store := ctx.KVStore(k.storeKey)
+	if !request.Requester.Empty() {
+		store.Set(types.RequesterRequestStoreKey(request.Requester, id), []byte{})
+	}
+	store.Set(types.OracleScriptRequestStoreKey(request.OracleScriptID, id), []byte{})
+	store.Set(types.ResolveStatusRequestStoreKey(request.ResolveStatus, id), []byte{})
+	for _, validator := range request.RequestedValidators {
+		store.Set(types.ValidatorRequestStoreKey(validator, id), []byte{})
+	}
+	if request.SourcePort != "" && request.SourceChannel != "" {
+		store.Set(types.SourceChannelRequestStoreKey(request.SourcePort, request.SourceChannel, id), []byte{})
+	}
+}
+
+// MigrateRequestIndexes adds every request stored before the secondary indexes existed to the
+// indexes used to search requests. Requests stored before the requester was recorded on requests
+// cannot be added to the requester index. Requests that are already indexed keep the same keys.
+func (k Keeper) MigrateRequestIndexes(ctx sdk.Context) {
+	requestCount := types.RequestID(k.GetRequestCount(ctx))
+	for id := types.RequestID(1); id <= requestCount; id++ {
+		request, err := k.GetRequest(ctx, id)
+		if err != nil { // should never happen, since pruned requests stay in the store
+			continue
+		}
+		k.setRequestSearchIndexes(ctx, id, request)
+	}
+}
+
+// reindexResolveStatus moves the request from the index of its old resolve status to the index of
+// its new one. The key of the new status is written even if the old key is missing, so a request
+// that was never indexed still ends up in the index of its current status.
+func (k Keeper) reindexResolveStatus(ctx sdk.Context, id types.RequestID, from, to types.ResolveStatus) {
+	store := ctx.KVStore(k.storeKey)
+	if from != to {
+		store.Delete(types.ResolveStatusRequestStoreKey(from, id))
+	}
+	store.Set(types.ResolveStatusRequestStoreKey(to, id), []byte{})
+}
+
+// GetRequestIDsByRequester returns the IDs of at most limit requests made by the given requester,
+// starting from startID in ascending order. Requests made before the requester was stored on
+// requests are not included.
+func (k Keeper) GetRequestIDsByRequester(
+	ctx sdk.Context, requester sdk.AccAddress, startID types.RequestID, limit int,
+) []types.RequestID {
+	store := ctx.KVStore(k.storeKey)
+	iterator := store.Iterator(
+		types.RequesterRequestStoreKey(requester, startID),
+		sdk.PrefixEndBytes(types.RequesterRequestsStoreKeyPrefix(requester)),
+	)
+	defer iterator.Close()
+
+	ids := make([]types.RequestID, 0)
+	for ; iterator.Valid() && len(ids) < limit; iterator.Next() {
+		ids = append(ids, types.GetRequestIDFromIndexKey(iterator.Key()))
+	}
+	return ids
+}
+
+// SearchRequests returns the IDs of at most params.Limit requests that match every filter of the
+// given search, starting from params.StartID in ascending order, along with the start ID of the
+// next page. The next ID is zero once there are no more requests to search. The search walks the
+// secondary index of one of the given filters, chosen by searchIndexPrefix, and checks the other
+// filters against each request. A page stops early with a non-zero next ID once
+// maxRequestSearchScan requests have been checked, so a page can be empty even though more
+// requests remain to be searched from its next ID.
+func (k Keeper) SearchRequests(
+	ctx sdk.Context, params types.QueryRequestsParams,
+) ([]types.RequestID, types.RequestID) {
+	firstID := types.RequestID(1)
+	lastID := types.RequestID(k.GetRequestCount(ctx))
+	if params.MinHeight > 0 {
+		firstID = k.firstRequestIDFromHeight(ctx, params.MinHeight)
+	}
+	if params.MaxHeight > 0 {
+		lastID = k.firstRequestIDFromHeight(ctx, params.MaxHeight+1) - 1
+	}
+	startID := params.StartID
+	if startID < firstID {
+		startID = firstID
+	}
+
+	ids := make([]types.RequestID, 0)
+	var nextID types.RequestID
+	scanned := 0
+	// visit checks a candidate request and returns whether the search should go on.
+	visit := func(id types.RequestID) bool {
+		if id > lastID {
+			return false
+		}
+		if len(ids) == params.Limit || scanned == maxRequestSearchScan {
+			nextID = id
+			return false
+		}
+		scanned++
+		request, err := k.GetRequest(ctx, id)
+		if err == nil && matchRequest(request, params) {
+			ids = append(ids, id)
+		}
+		return true
+	}
+
+	prefix := searchIndexPrefix(params)
+	if prefix == nil {
+		for id := startID; id <= lastID; id++ {
+			if !visit(id) {
+				break
+			}
+		}
+		return ids, nextID
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	iterator := store.Iterator(types.GetIteratorPrefix(prefix, startID), sdk.PrefixEndBytes(prefix))
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		if !visit(types.GetRequestIDFromIndexKey(iterator.Key())) {
+			break
+		}
+	}
+	return ids, nextID
+}
+
+// firstRequestIDFromHeight returns the ID of the first request made at or after the given height.
+// Request IDs grow with request heights, so it can be found by binary search.
+func (k Keeper) firstRequestIDFromHeight(ctx sdk.Context, height int64) types.RequestID {
+	requestCount := int(k.GetRequestCount(ctx))
+	index := sort.Search(requestCount, func(i int) bool {
+		request, err := k.GetRequest(ctx, types.RequestID(i+1))
+		return err != nil || request.RequestHeight >= height
+	})
+	return types.RequestID(index + 1)
+}
+
+// searchIndexPrefix returns the prefix of the secondary index that the search walks, or nil if the
+// search has no indexed filter. The index is picked in a fixed order, requester, validator, oracle
+// script, source channel and then resolve status, regardless of how many requests each index
+// actually holds.
+func searchIndexPrefix(params types.QueryRequestsParams) []byte {
+	switch {
+	case !params.Requester.Empty():
+		return types.RequesterRequestsStoreKeyPrefix(params.Requester)
+	case !params.Validator.Empty():
+		return types.ValidatorRequestsStoreKeyPrefix(params.Validator)
+	case params.OracleScriptID != 0:
+		return types.OracleScriptRequestsStoreKeyPrefix(params.OracleScriptID)
+	case params.SourceChannel != "":
+		return types.SourceChannelRequestsStoreKeyPrefix(params.SourcePort, params.SourceChannel)
+	case params.ResolveStatus != "":
+		status, _ := types.ResolveStatusFromString(params.ResolveStatus)
+		return types.ResolveStatusRequestsStoreKeyPrefix(status)
+	default:
+		return nil
+	}
+}
+
+// matchRequest returns whether the request matches every filter of the search except the height
+// range, which is applied to request IDs.
+func matchRequest(request types.Request, params types.QueryRequestsParams) bool {
+	if !params.Requester.Empty() && !request.Requester.Equals(params.Requester) {
+		return false
+	}
+	if params.OracleScriptID != 0 && request.OracleScriptID != params.OracleScriptID {
+		return false
+	}
+	if params.ResolveStatus != "" {
+		status, err := types.ResolveStatusFromString(params.ResolveStatus)
+		if err != nil || request.ResolveStatus != status {
+			return false
+		}
+	}
+	if params.SourceChannel != "" &&
+		(request.SourcePort != params.SourcePort || request.SourceChannel != params.SourceChannel) {
+		return false
+	}
+	if !params.Validator.Empty() {
+		for _, validator := range request.RequestedValidators {
+			if validator.Equals(params.Validator) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,319 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+// setIndexedTestRequest stores and indexes an open request to the given oracle script made at the
+// given height, and returns its ID.
+func setIndexedTestRequest(
+	ctx sdk.Context, keeper Keeper, height int64, oracleScriptID types.OracleScriptID, sourceChannel string,
+) types.RequestID {
+	id := keeper.GetNextRequestID(ctx)
+	sourcePort := ""
+	if sourceChannel != "" {
+		sourcePort = "port"
+	}
+	request := types.NewRequest(
+		oracleScriptID, []byte("calldata"), []sdk.ValAddress{sdk.ValAddress([]byte("validator"))}, 1,
+		height, 0, height+100, 1000, sourcePort, sourceChannel, 0, sdk.Coins{},
+		sdk.AccAddress([]byte("requester")),
+	)
+	keeper.SetRequest(ctx, id, request)
+	keeper.indexRequest(ctx, id, request)
+	return id
+}
+
+// equalRequestIDs returns whether the two lists hold the same request IDs in the same order.
+func equalRequestIDs(ids, expected []types.RequestID) bool {
+	if len(ids) != len(expected) {
+		return false
+	}
+	for i := range ids {
+		if ids[i] != expected[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSearchRequestsPaging(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	for _, oracleScriptID := range []types.OracleScriptID{1, 2, 1, 1, 2, 1, 1} {
+		setIndexedTestRequest(ctx, keeper, 1, oracleScriptID, "")
+	}
+
+	params := types.QueryRequestsParams{StartID: 1, Limit: 2, OracleScriptID: 1}
+	var pages [][]types.RequestID
+	for {
+		ids, nextID := keeper.SearchRequests(ctx, params)
+		pages = append(pages, ids)
+		if nextID == 0 {
+			break
+		}
+		if nextID <= params.StartID {
+			t.Fatalf("next ID %d does not move past start ID %d", nextID, params.StartID)
+		}
+		params.StartID = nextID
+	}
+
+	expected := [][]types.RequestID{{1, 3}, {4, 6}, {7}}
+	if len(pages) != len(expected) {
+		t.Fatalf("pages %v, expected %v", pages, expected)
+	}
+	for i := range expected {
+		if !equalRequestIDs(pages[i], expected[i]) {
+			t.Errorf("page %d: %v, expected %v", i, pages[i], expected[i])
+		}
+	}
+
+	// Without an indexed filter, the search walks request IDs and pages the same way.
+	ids, nextID := keeper.SearchRequests(ctx, types.QueryRequestsParams{StartID: 3, Limit: 3})
+	if !equalRequestIDs(ids, []types.RequestID{3, 4, 5}) || nextID != 6 {
+		t.Errorf("unfiltered page %v with next ID %d, expected [3 4 5] with next ID 6", ids, nextID)
+	}
+}
+
+func TestSearchRequestsScanCap(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	// Only the last request matches the channel filter, and the search walks the oracle script
+	// index, which holds every request.
+	for i := 0; i < maxRequestSearchScan; i++ {
+		setIndexedTestRequest(ctx, keeper, 1, 1, "")
+	}
+	last := setIndexedTestRequest(ctx, keeper, 1, 1, "channel")
+
+	params := types.QueryRequestsParams{
+		StartID: 1, Limit: 10, OracleScriptID: 1, SourcePort: "port", SourceChannel: "channel",
+	}
+	ids, nextID := keeper.SearchRequests(ctx, params)
+	if len(ids) != 0 || nextID != last {
+		t.Fatalf("first page %v with next ID %d, expected no match with next ID %d", ids, nextID, last)
+	}
+
+	// The querier serves the empty page along with the next ID.
+	bz, err := NewQuerier(keeper)(
+		ctx, []string{types.QuerySearchRequests}, abci.RequestQuery{Data: keeper.cdc.MustMarshalJSON(params)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result types.RequestSearchResult
+	keeper.cdc.MustUnmarshalJSON(bz, &result)
+	if len(result.Requests) != 0 || result.NextID != last {
+		t.Errorf("queried page %+v, expected no requests with next ID %d", result, last)
+	}
+
+	params.StartID = nextID
+	ids, nextID = keeper.SearchRequests(ctx, params)
+	if !equalRequestIDs(ids, []types.RequestID{last}) || nextID != 0 {
+		t.Errorf("second page %v with next ID %d, expected [%d] with next ID 0", ids, nextID, last)
+	}
+}
+
+func TestSearchRequestsByHeight(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	for _, height := range []int64{1, 1, 3, 3, 3, 7} {
+		setIndexedTestRequest(ctx, keeper, height, 1, "")
+	}
+
+	for height, expected := range map[int64]types.RequestID{0: 1, 1: 1, 2: 3, 3: 3, 4: 6, 7: 6, 8: 7} {
+		if id := keeper.firstRequestIDFromHeight(ctx, height); id != expected {
+			t.Errorf("height %d: first request ID %d, expected %d", height, id, expected)
+		}
+	}
+
+	cases := []struct {
+		minHeight, maxHeight int64
+		expected             []types.RequestID
+	}{
+		{3, 3, []types.RequestID{3, 4, 5}},
+		{2, 6, []types.RequestID{3, 4, 5}},
+		{4, 0, []types.RequestID{6}},
+		{0, 2, []types.RequestID{1, 2}},
+		{4, 6, []types.RequestID{}},
+		{8, 0, []types.RequestID{}},
+	}
+	for _, c := range cases {
+		params := types.QueryRequestsParams{StartID: 1, Limit: 10, MinHeight: c.minHeight, MaxHeight: c.maxHeight}
+		if ids, _ := keeper.SearchRequests(ctx, params); !equalRequestIDs(ids, c.expected) {
+			t.Errorf("heights %d to %d: %v, expected %v", c.minHeight, c.maxHeight, ids, c.expected)
+		}
+		params.OracleScriptID = 1
+		if ids, _ := keeper.SearchRequests(ctx, params); !equalRequestIDs(ids, c.expected) {
+			t.Errorf("heights %d to %d by oracle script: %v, expected %v", c.minHeight, c.maxHeight, ids, c.expected)
+		}
+	}
+}
+
+func TestMigrateRequestIndexes(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	validator := sdk.ValAddress([]byte("validator"))
+	requester := sdk.AccAddress([]byte("requester"))
+	// Requests 1 and 2 were stored before the indexes existed, and request 1 also before the
+	// requester was recorded.
+	for id, requestRequester := range map[types.RequestID]sdk.AccAddress{1: nil, 2: requester} {
+		keeper.SetRequest(ctx, id, types.NewRequest(
+			3, []byte("calldata"), []sdk.ValAddress{validator}, 1, 1, 0, 101, 1000, "port", "channel", 0,
+			sdk.Coins{}, requestRequester,
+		))
+		keeper.GetNextRequestID(ctx)
+	}
+	if err := keeper.SetResolve(ctx, 2, types.Success, types.FailureReasonNone); err != nil {
+		t.Fatal(err)
+	}
+
+	keeper.MigrateRequestIndexes(ctx)
+	keeper.MigrateRequestIndexes(ctx)
+
+	cases := map[string]struct {
+		params   types.QueryRequestsParams
+		expected []types.RequestID
+	}{
+		"oracle script":  {types.QueryRequestsParams{OracleScriptID: 3}, []types.RequestID{1, 2}},
+		"validator":      {types.QueryRequestsParams{Validator: validator}, []types.RequestID{1, 2}},
+		"source channel": {types.QueryRequestsParams{SourcePort: "port", SourceChannel: "channel"}, []types.RequestID{1, 2}},
+		"open":           {types.QueryRequestsParams{ResolveStatus: "open"}, []types.RequestID{1}},
+		"success":        {types.QueryRequestsParams{ResolveStatus: "success"}, []types.RequestID{2}},
+	}
+	for name, c := range cases {
+		c.params.StartID = 1
+		c.params.Limit = 10
+		if ids, _ := keeper.SearchRequests(ctx, c.params); !equalRequestIDs(ids, c.expected) {
+			t.Errorf("%s: %v, expected %v", name, ids, c.expected)
+		}
+	}
+	if ids := keeper.GetRequestIDsByRequester(ctx, requester, 1, 10); !equalRequestIDs(ids, []types.RequestID{2}) {
+		t.Errorf("requests by requester %v, expected [2]", ids)
+	}
+
+	// The status index only holds the current status, after the migration and after resolving.
+	ctx.KVStore(keeper.storeKey).Delete(types.ResolveStatusRequestStoreKey(types.Open, 1))
+	if err := keeper.SetResolve(ctx, 1, types.Cancelled, types.FailureReasonNone); err != nil {
+		t.Fatal(err)
+	}
+	for status, expected := range map[string][]types.RequestID{"open": {}, "cancelled": {1}, "success": {2}} {
+		params := types.QueryRequestsParams{StartID: 1, Limit: 10, ResolveStatus: status}
+		if ids, _ := keeper.SearchRequests(ctx, params); !equalRequestIDs(ids, expected) {
+			t.Errorf("%s after resolving: %v, expected %v", status, ids, expected)
+		}
+	}
+}
+
+// setRequesterTestRequest stores and indexes an open request made by the given requester, and
+// returns its ID.
+func setRequesterTestRequest(ctx sdk.Context, keeper Keeper, requester sdk.AccAddress) types.RequestID {
+	id := keeper.GetNextRequestID(ctx)
+	request := types.NewRequest(
+		1, []byte("calldata"), []sdk.ValAddress{sdk.ValAddress([]byte("validator___________"))}, 1,
+		1, 0, 101, 1000, "", "", 0, sdk.Coins{}, requester,
+	)
+	keeper.SetRequest(ctx, id, request)
+	keeper.indexRequest(ctx, id, request)
+	return id
+}
+
+func TestGetRequestIDsByRequester(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	requester := sdk.AccAddress([]byte("requester"))
+	other := sdk.AccAddress([]byte("other"))
+	for _, address := range []sdk.AccAddress{requester, other, requester, requester, other, requester} {
+		setRequesterTestRequest(ctx, keeper, address)
+	}
+
+	cases := []struct {
+		requester sdk.AccAddress
+		startID   types.RequestID
+		limit     int
+		expected  []types.RequestID
+	}{
+		{requester, 1, 10, []types.RequestID{1, 3, 4, 6}},
+		{requester, 1, 2, []types.RequestID{1, 3}},
+		{requester, 2, 2, []types.RequestID{3, 4}},
+		{requester, 4, 10, []types.RequestID{4, 6}},
+		{requester, 7, 10, []types.RequestID{}},
+		{other, 1, 10, []types.RequestID{2, 5}},
+		{sdk.AccAddress([]byte("nobody")), 1, 10, []types.RequestID{}},
+	}
+	for _, c := range cases {
+		ids := keeper.GetRequestIDsByRequester(ctx, c.requester, c.startID, c.limit)
+		if !equalRequestIDs(ids, c.expected) {
+			t.Errorf("requests by %s from %d limit %d: %v, expected %v", c.requester, c.startID, c.limit, ids, c.expected)
+		}
+	}
+}
+
+func TestQueryRequestsByRequester(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	requester := sdk.AccAddress([]byte("requester___________"))
+	setRequesterTestRequest(ctx, keeper, requester)
+	setRequesterTestRequest(ctx, keeper, sdk.AccAddress([]byte("other")))
+	setRequesterTestRequest(ctx, keeper, requester)
+	querier := NewQuerier(keeper)
+
+	bz, err := querier(ctx, []string{types.QueryRequestsByRequester, requester.String(), "2", "10"}, abci.RequestQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requests []types.RequestQuerierInfo
+	keeper.cdc.MustUnmarshalJSON(bz, &requests)
+	if len(requests) != 1 || requests[0].ID != 3 {
+		t.Errorf("requests %+v, expected request 3 only", requests)
+	}
+
+	bad := map[string][]string{
+		"missing arguments":    {requester.String(), "1"},
+		"bad address":          {"requester", "1", "10"},
+		"bad start id":         {requester.String(), "first", "10"},
+		"zero start id":        {requester.String(), "0", "10"},
+		"bad count":            {requester.String(), "1", "ten"},
+		"zero count":           {requester.String(), "1", "0"},
+		"count over the limit": {requester.String(), "1", "101"},
+	}
+	for name, args := range bad {
+		path := append([]string{types.QueryRequestsByRequester}, args...)
+		if _, err := querier(ctx, path, abci.RequestQuery{}); err == nil {
+			t.Errorf("%s: expected the query to be rejected", name)
+		}
+	}
+}
+
+func TestQueryRequestsSkipsMissingRequests(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	requester := sdk.AccAddress([]byte("requester___________"))
+	setRequesterTestRequest(ctx, keeper, requester)
+	// A request ID that was taken without storing a request.
+	keeper.GetNextRequestID(ctx)
+	setRequesterTestRequest(ctx, keeper, requester)
+	querier := NewQuerier(keeper)
+
+	for _, args := range [][]string{{"1", "3"}, {"1", "100"}} {
+		bz, err := querier(ctx, append([]string{types.QueryRequests}, args...), abci.RequestQuery{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var requests []types.RequestQuerierInfo
+		keeper.cdc.MustUnmarshalJSON(bz, &requests)
+		if len(requests) != 2 || requests[0].ID != 1 || requests[1].ID != 3 {
+			t.Errorf("requests %v: got %+v, expected requests 1 and 3", args, requests)
+		}
+	}
+
+	bad := map[string][]string{
+		"missing arguments":    {"1"},
+		"bad start id":         {"first", "10"},
+		"bad count":            {"1", "ten"},
+		"zero count":           {"1", "0"},
+		"count over the limit": {"1", "101"},
+	}
+	for name, args := range bad {
+		if _, err := querier(ctx, append([]string{types.QueryRequests}, args...), abci.RequestQuery{}); err == nil {
+			t.Errorf("%s: expected the query to be rejected", name)
+		}
+	}
+}
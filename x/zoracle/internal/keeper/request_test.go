@@ -0,0 +1,250 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+// setTestRequest stores an open request to oracle script 1 with the given execute gas and
+// priority fee, and returns its ID.
+func setTestRequest(
+	ctx sdk.Context, keeper Keeper, executeGas uint64, priorityFee sdk.Coins,
+) types.RequestID {
+	id := keeper.GetNextRequestID(ctx)
+	keeper.SetRequest(ctx, id, types.NewRequest(
+		1, []byte("calldata"), []sdk.ValAddress{sdk.ValAddress([]byte("validator"))}, 1,
+		ctx.BlockHeight(), ctx.BlockTime().Unix(), ctx.BlockHeight()+100, executeGas, "", "", 0,
+		priorityFee, sdk.AccAddress([]byte("requester")),
+	))
+	return id
+}
+
+func TestMigratePendingResolveListStartsWaitAtUpgrade(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	first := setTestRequest(ctx, keeper, 1000, sdk.Coins{})
+	second := setTestRequest(ctx, keeper, 1000, sdk.Coins{})
+	store := ctx.KVStore(keeper.storeKey)
+	store.Set(types.PendingResolveListStoreKey, keeper.cdc.MustMarshalBinaryBare([]types.RequestID{second, first}))
+
+	ctx = ctx.WithBlockHeight(500)
+	keeper.MigratePendingResolveList(ctx)
+
+	pending := keeper.GetPendingResolveList(ctx)
+	if len(pending) != 2 || pending[0] != second || pending[1] != first {
+		t.Fatalf("pending list %v, expected [%d %d]", pending, second, first)
+	}
+	for _, id := range pending {
+		request, _ := keeper.GetRequest(ctx, id)
+		if request.PendingHeight != 500 {
+			t.Errorf("request %d: pending height %d, expected the upgrade height", id, request.PendingHeight)
+		}
+	}
+	if store.Has(types.PendingResolveListStoreKey) {
+		t.Error("expected the legacy pending list to be deleted")
+	}
+}
+
+func TestLegacyPendingRequestIsNotAged(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	bondDenom := keeper.StakingKeeper.BondDenom(ctx)
+	legacy := setTestRequest(ctx, keeper, 1000, sdk.Coins{})
+	keeper.appendPendingRequest(ctx, legacy)
+	paying := setTestRequest(ctx, keeper, 1000, sdk.NewCoins(sdk.NewInt64Coin(bondDenom, 10)))
+	if err := keeper.AddPendingRequest(ctx, paying); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a pending height the legacy request would count as waiting since height 0 and be
+	// served first as an aged request.
+	request, _ := keeper.GetRequest(ctx, legacy)
+	if waited := request.PendingBlocks(1000); waited != 0 {
+		t.Errorf("legacy request waited %d blocks, expected 0", waited)
+	}
+	order := keeper.GetPendingRequestsInScheduleOrder(ctx)
+	if len(order) != 2 || order[0] != paying || order[1] != legacy {
+		t.Errorf("schedule order %v, expected [%d %d]", order, paying, legacy)
+	}
+}
+
+func TestScheduleOrderByPriorityFeePerGas(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	bondDenom := keeper.StakingKeeper.BondDenom(ctx)
+	fee := func(amount int64) sdk.Coins { return sdk.NewCoins(sdk.NewInt64Coin(bondDenom, amount)) }
+
+	free := setTestRequest(ctx, keeper, 1000, sdk.Coins{})
+	cheap := setTestRequest(ctx, keeper, 1000, fee(10))
+	// 30 per 2000 gas pays more per gas than 10 per 1000, but less than 20 per 1000.
+	large := setTestRequest(ctx, keeper, 2000, fee(30))
+	rich := setTestRequest(ctx, keeper, 1000, fee(20))
+	tied := setTestRequest(ctx, keeper, 4000, fee(80))
+	otherDenom := setTestRequest(ctx, keeper, 1000, sdk.NewCoins(sdk.NewInt64Coin("other", 1000)))
+	for _, id := range []types.RequestID{free, cheap, large, rich, tied, otherDenom} {
+		if err := keeper.AddPendingRequest(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Other denominations do not count, and ties keep the order the requests became pending.
+	expected := []types.RequestID{rich, tied, large, cheap, free, otherDenom}
+	order := keeper.GetPendingRequestsInScheduleOrder(ctx)
+	if len(order) != len(expected) {
+		t.Fatalf("schedule order %v, expected %v", order, expected)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("schedule order %v, expected %v", order, expected)
+		}
+	}
+
+	// Requests pending for PendingAgingBlocks go first regardless of their fee.
+	ctx = ctx.WithBlockHeight(ctx.BlockHeight() + keeper.PendingAgingBlocks(ctx))
+	aged := setTestRequest(ctx, keeper, 1000, fee(1000))
+	if err := keeper.AddPendingRequest(ctx, aged); err != nil {
+		t.Fatal(err)
+	}
+	order = keeper.GetPendingRequestsInScheduleOrder(ctx)
+	if order[0] != free || order[len(order)-1] != aged {
+		t.Errorf("schedule order %v, expected aged requests first in FIFO order", order)
+	}
+}
+
+func TestEscrowPriorityFeeRequiresBondDenom(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	bondDenom := keeper.StakingKeeper.BondDenom(ctx)
+	sender := sdk.AccAddress([]byte("sender"))
+	FundTestAccount(ctx, keeper, sender, sdk.NewCoins(sdk.NewInt64Coin(bondDenom, 100), sdk.NewInt64Coin("other", 100)))
+
+	if err := keeper.EscrowPriorityFee(ctx, sender, sdk.NewCoins(sdk.NewInt64Coin("other", 10))); err == nil {
+		t.Error("expected a priority fee in another denomination to be rejected")
+	}
+	if err := keeper.EscrowPriorityFee(ctx, sender, sdk.NewCoins(sdk.NewInt64Coin(bondDenom, 10))); err != nil {
+		t.Fatal(err)
+	}
+	if balance := keeper.CoinKeeper.GetBalance(ctx, sender, bondDenom); !balance.Amount.Equal(sdk.NewInt(90)) {
+		t.Errorf("sender has %s left, expected 90%s", balance, bondDenom)
+	}
+}
+
+func TestPruneRefundsPriorityFeeOfExpiredRequest(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	bondDenom := keeper.StakingKeeper.BondDenom(ctx)
+	requester := sdk.AccAddress([]byte("requester"))
+	fee := sdk.NewCoins(sdk.NewInt64Coin(bondDenom, 10))
+	FundTestAccount(ctx, keeper, requester, fee)
+	if err := keeper.EscrowPriorityFee(ctx, requester, fee); err != nil {
+		t.Fatal(err)
+	}
+	id := setTestRequest(ctx, keeper, 1000, fee)
+	request, _ := keeper.GetRequest(ctx, id)
+
+	keeper.SetResultRetentionBlocks(ctx, 1)
+	ctx = ctx.WithBlockHeight(request.ExpirationHeight + 1)
+	if pruned := keeper.PruneRequests(ctx); pruned != 1 {
+		t.Fatalf("pruned %d requests, expected 1", pruned)
+	}
+	if balance := keeper.CoinKeeper.GetBalance(ctx, requester, bondDenom); !balance.Amount.Equal(sdk.NewInt(10)) {
+		t.Errorf("requester has %s, expected the priority fee back", balance)
+	}
+}
+
+func TestCancelRequest(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	bondDenom := keeper.StakingKeeper.BondDenom(ctx)
+	requester := sdk.AccAddress([]byte("requester"))
+	fee := sdk.NewCoins(sdk.NewInt64Coin(bondDenom, 10))
+	FundTestAccount(ctx, keeper, requester, fee)
+	if err := keeper.EscrowPriorityFee(ctx, requester, fee); err != nil {
+		t.Fatal(err)
+	}
+	id := setTestRequest(ctx, keeper, 1000, fee)
+
+	if err := keeper.CancelRequest(ctx, id, sdk.AccAddress([]byte("someone else"))); err == nil {
+		t.Error("expected only the requester to be able to cancel")
+	}
+
+	if err := keeper.CancelRequest(ctx, id, requester); err != nil {
+		t.Fatal(err)
+	}
+	request, _ := keeper.GetRequest(ctx, id)
+	if request.ResolveStatus != types.Cancelled {
+		t.Errorf("resolve status %d, expected cancelled", request.ResolveStatus)
+	}
+	if balance := keeper.CoinKeeper.GetBalance(ctx, requester, bondDenom); !balance.Amount.Equal(sdk.NewInt(10)) {
+		t.Errorf("requester has %s, expected the priority fee back", balance)
+	}
+	if err := keeper.CancelRequest(ctx, id, requester); err == nil {
+		t.Error("expected a cancelled request not to be cancelled again")
+	}
+}
+
+func TestCancelExpiredRequest(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	bondDenom := keeper.StakingKeeper.BondDenom(ctx)
+	requester := sdk.AccAddress([]byte("requester"))
+	fee := sdk.NewCoins(sdk.NewInt64Coin(bondDenom, 10))
+	moduleAddress := keeper.SupplyKeeper.GetModuleAddress(types.ModuleName)
+	// Escrow the priority fees of two requests, so that a second refund would not fail.
+	FundTestAccount(ctx, keeper, requester, fee.Add(fee...))
+	if err := keeper.EscrowPriorityFee(ctx, requester, fee.Add(fee...)); err != nil {
+		t.Fatal(err)
+	}
+	id := setTestRequest(ctx, keeper, 1000, fee)
+	setTestRequest(ctx, keeper, 1000, fee)
+	request, _ := keeper.GetRequest(ctx, id)
+	keeper.scheduleExpiration(ctx, id, request)
+
+	ctx = ctx.WithBlockHeight(request.ExpirationHeight)
+	if expired := keeper.ExpireRequests(ctx); !equalRequestIDs(expired, []types.RequestID{id}) {
+		t.Fatalf("expired %v, expected [%d]", expired, id)
+	}
+	if request, _ := keeper.GetRequest(ctx, id); !request.PriorityFee.IsZero() {
+		t.Errorf("priority fee %s after the refund, expected zero", request.PriorityFee)
+	}
+	if balance := keeper.CoinKeeper.GetBalance(ctx, moduleAddress, bondDenom); !balance.Amount.Equal(sdk.NewInt(10)) {
+		t.Fatalf("module account has %s after the expiry, expected 10", balance)
+	}
+
+	if err := keeper.CancelRequest(ctx.WithBlockHeight(request.ExpirationHeight+1), id, requester); err == nil {
+		t.Error("expected an expired request not to be cancelled")
+	}
+	if balance := keeper.CoinKeeper.GetBalance(ctx, moduleAddress, bondDenom); !balance.Amount.Equal(sdk.NewInt(10)) {
+		t.Errorf("module account has %s after the cancel, expected the fee to be refunded only once", balance)
+	}
+	if balance := keeper.CoinKeeper.GetBalance(ctx, requester, bondDenom); !balance.Amount.Equal(sdk.NewInt(10)) {
+		t.Errorf("requester has %s, expected one priority fee back", balance)
+	}
+}
+
+func TestCancelRequestPastExpiration(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	requester := sdk.AccAddress([]byte("requester"))
+	id := setTestRequest(ctx, keeper, 1000, sdk.Coins{})
+	request, _ := keeper.GetRequest(ctx, id)
+
+	if err := keeper.CancelRequest(ctx.WithBlockHeight(request.ExpirationHeight+1), id, requester); err == nil {
+		t.Error("expected a request past its expiration height not to be cancelled")
+	}
+	request.Pruned = true
+	keeper.SetRequest(ctx, id, request)
+	if err := keeper.CancelRequest(ctx, id, requester); err == nil {
+		t.Error("expected a pruned request not to be cancelled")
+	}
+}
+
+func TestCancelRequestWithSufficientReports(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	requester := sdk.AccAddress([]byte("requester"))
+	id := setTestRequest(ctx, keeper, 1000, sdk.Coins{})
+	request, _ := keeper.GetRequest(ctx, id)
+	request.ReceivedValidators = request.RequestedValidators
+	keeper.SetRequest(ctx, id, request)
+
+	if err := keeper.CancelRequest(ctx, id, requester); err == nil {
+		t.Error("expected a request with sufficient reports not to be cancelled")
+	}
+	if request, _ := keeper.GetRequest(ctx, id); request.ResolveStatus != types.Open {
+		t.Errorf("resolve status %d, expected the request to stay open", request.ResolveStatus)
+	}
+}
@@ -1,6 +1,9 @@
 package keeper
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
+
 	"github.com/cosmos/gaia/x/zoracle/internal/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
@@ -23,6 +26,10 @@ func (k Keeper) AddResult(
 		return err
 	}
 
+	resultHash := sha256.Sum256(result)
+	request.ResultHash = resultHash[:]
+	k.SetRequest(ctx, requestID, request)
+
 	k.SetResult(ctx, requestID, oracleScriptID, calldata, types.NewResult(
 		request.RequestTime,
 		ctx.BlockTime().Unix(),
@@ -31,6 +38,7 @@ func (k Keeper) AddResult(
 		int64(len(request.ReceivedValidators)),
 		result,
 	))
+	k.setLatestResultRequestID(ctx, oracleScriptID, calldata, requestID)
 
 	return nil
 }
@@ -67,3 +75,64 @@ func (k Keeper) HasResult(ctx sdk.Context, requestID types.RequestID, oracleScri
 	store := ctx.KVStore(k.storeKey)
 	return store.Has(types.ResultStoreKey(requestID, oracleScriptID, calldata))
 }
+
+// setLatestResultRequestID records the request as the latest one with a result for its oracle
+// script and calldata, unless a newer request already has a result. The result of the previous
+// latest request is deleted if that request has been pruned, since pruning kept it only for
+// being the latest.
+func (k Keeper) setLatestResultRequestID(
+	ctx sdk.Context, oracleScriptID types.OracleScriptID, calldata []byte, requestID types.RequestID,
+) {
+	store := ctx.KVStore(k.storeKey)
+	latestID, err := k.GetLatestResultRequestID(ctx, oracleScriptID, calldata)
+	if err == nil {
+		if latestID >= requestID {
+			return
+		}
+		latestRequest, err := k.GetRequest(ctx, latestID)
+		if err == nil && latestRequest.Pruned {
+			store.Delete(types.ResultStoreKey(latestID, oracleScriptID, calldata))
+		}
+	}
+	store.Set(types.LatestResultStoreKey(oracleScriptID, calldata), sdk.Uint64ToBigEndian(uint64(requestID)))
+}
+
+// GetLatestResultRequestID returns the ID of the latest request with a result for the given
+// oracle script and calldata.
+func (k Keeper) GetLatestResultRequestID(
+	ctx sdk.Context, oracleScriptID types.OracleScriptID, calldata []byte,
+) (types.RequestID, error) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.LatestResultStoreKey(oracleScriptID, calldata))
+	if bz == nil {
+		return 0, sdkerrors.Wrapf(types.ErrItemNotFound,
+			"GetLatestResultRequestID: No result for oracle script ID %d with the given calldata.",
+			oracleScriptID,
+		)
+	}
+	return types.RequestID(binary.BigEndian.Uint64(bz)), nil
+}
+
+// GetLatestResult returns the result of the latest request with a result for the given oracle
+// script and calldata.
+func (k Keeper) GetLatestResult(
+	ctx sdk.Context, oracleScriptID types.OracleScriptID, calldata []byte,
+) (types.Result, error) {
+	requestID, err := k.GetLatestResultRequestID(ctx, oracleScriptID, calldata)
+	if err != nil {
+		return types.Result{}, err
+	}
+	return k.GetResult(ctx, requestID, oracleScriptID, calldata)
+}
+
+// MigrateLatestResults indexes the latest result of each oracle script and calldata among the
+// results stored before the index existed.
+func (k Keeper) MigrateLatestResults(ctx sdk.Context) {
+	iterator := sdk.KVStorePrefixIterator(ctx.KVStore(k.storeKey), types.ResultStoreKeyPrefix)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		requestID, oracleScriptID, calldata := types.GetRequestIDOracleScriptIDAndCalldataFromResultKey(iterator.Key())
+		k.setLatestResultRequestID(ctx, oracleScriptID, calldata, requestID)
+	}
+}
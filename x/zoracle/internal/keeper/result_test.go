@@ -0,0 +1,96 @@
+package keeper
+
+import (
+	"bytes"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+func TestAddResultUpdatesLatestResult(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	first := setTestRequest(ctx, keeper, 1000, sdk.Coins{})
+	second := setTestRequest(ctx, keeper, 1000, sdk.Coins{})
+	other := setTestRequest(ctx, keeper, 1000, sdk.Coins{})
+
+	if _, err := keeper.GetLatestResult(ctx, 1, []byte("calldata")); err == nil {
+		t.Error("expected no latest result before any request is resolved")
+	}
+	if err := keeper.AddResult(ctx, second, 1, []byte("calldata"), []byte("second")); err != nil {
+		t.Fatal(err)
+	}
+	// A request resolved later but made earlier does not replace the latest result.
+	if err := keeper.AddResult(ctx, first, 1, []byte("calldata"), []byte("first")); err != nil {
+		t.Fatal(err)
+	}
+	if err := keeper.AddResult(ctx, other, 1, []byte("other"), []byte("other")); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := keeper.GetLatestResult(ctx, 1, []byte("calldata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(result.Data, []byte("second")) {
+		t.Errorf("latest result %q, expected \"second\"", result.Data)
+	}
+	if id, _ := keeper.GetLatestResultRequestID(ctx, 1, []byte("other")); id != other {
+		t.Errorf("latest request %d for other calldata, expected %d", id, other)
+	}
+	if _, err := keeper.GetLatestResult(ctx, 2, []byte("calldata")); err == nil {
+		t.Error("expected no latest result for another oracle script")
+	}
+}
+
+func TestAddResultDeletesReplacedPrunedResult(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	first := setTestRequest(ctx, keeper, 1000, sdk.Coins{})
+	second := setTestRequest(ctx, keeper, 1000, sdk.Coins{})
+	if err := keeper.AddResult(ctx, first, 1, []byte("calldata"), []byte("first")); err != nil {
+		t.Fatal(err)
+	}
+	// Pruning kept the result of the first request only because it was the latest one.
+	request, _ := keeper.GetRequest(ctx, first)
+	request.Pruned = true
+	keeper.SetRequest(ctx, first, request)
+
+	if err := keeper.AddResult(ctx, second, 1, []byte("calldata"), []byte("second")); err != nil {
+		t.Fatal(err)
+	}
+	if keeper.HasResult(ctx, first, 1, []byte("calldata")) {
+		t.Error("expected the replaced result of the pruned request to be deleted")
+	}
+	if !keeper.HasResult(ctx, second, 1, []byte("calldata")) {
+		t.Error("expected the new latest result to be kept")
+	}
+}
+
+func TestMigrateLatestResults(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	// Results stored before the latest result index existed.
+	for _, id := range []types.RequestID{1, 3, 2} {
+		keeper.SetResult(ctx, id, 1, []byte("calldata"), types.NewResult(0, 0, 1, 1, 1, []byte{byte(id)}))
+	}
+	keeper.SetResult(ctx, 4, 1, []byte("other"), types.NewResult(0, 0, 1, 1, 1, []byte{4}))
+	keeper.SetResult(ctx, 5, 2, []byte("calldata"), types.NewResult(0, 0, 1, 1, 1, []byte{5}))
+
+	keeper.MigrateLatestResults(ctx)
+
+	cases := []struct {
+		oracleScriptID types.OracleScriptID
+		calldata       string
+		expected       types.RequestID
+	}{
+		{1, "calldata", 3},
+		{1, "other", 4},
+		{2, "calldata", 5},
+	}
+	for _, c := range cases {
+		id, err := keeper.GetLatestResultRequestID(ctx, c.oracleScriptID, []byte(c.calldata))
+		if err != nil || id != c.expected {
+			t.Errorf("oracle script %d calldata %q: latest request %d (%v), expected %d",
+				c.oracleScriptID, c.calldata, id, err, c.expected)
+		}
+	}
+}
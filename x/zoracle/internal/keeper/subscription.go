@@ -0,0 +1,241 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+// AddSubscription escrows the deposit of the owner and schedules the first request of a new
+// subscription at the next block.
+func (k Keeper) AddSubscription(
+	ctx sdk.Context, owner sdk.AccAddress, oracleScriptID types.OracleScriptID, calldata []byte,
+	requestedValidatorCount, sufficientValidatorCount, expiration int64, prepareGas, executeGas uint64,
+	interval, endHeight int64, deposit sdk.Coins,
+) (types.SubscriptionID, error) {
+	if !k.CheckOracleScriptExists(ctx, oracleScriptID) {
+		return 0, sdkerrors.Wrapf(types.ErrItemNotFound,
+			"AddSubscription: Unknown oracle script ID %d.",
+			oracleScriptID,
+		)
+	}
+
+	if int64(len(calldata)) > k.MaxCalldataSize(ctx) {
+		return 0, sdkerrors.Wrapf(types.ErrBadDataValue,
+			"AddSubscription: Calldata size (%d) exceeds the maximum size (%d).",
+			len(calldata),
+			int(k.MaxCalldataSize(ctx)),
+		)
+	}
+
+	if executeGas > k.EndBlockExecuteGasLimit(ctx) {
+		return 0, sdkerrors.Wrapf(types.ErrBadDataValue,
+			"AddSubscription: Execute gas (%d) exceeds the maximum limit (%d).",
+			executeGas,
+			k.EndBlockExecuteGasLimit(ctx),
+		)
+	}
+
+	nextHeight := ctx.BlockHeight() + 1
+	if endHeight < nextHeight {
+		return 0, sdkerrors.Wrapf(types.ErrBadDataValue,
+			"AddSubscription: End height (%d) must not be before the next block (%d).",
+			endHeight,
+			nextHeight,
+		)
+	}
+
+	if endHeight-nextHeight > k.MaxSubscriptionDuration(ctx) {
+		return 0, sdkerrors.Wrapf(types.ErrBadDataValue,
+			"AddSubscription: End height (%d) is more than the maximum duration (%d) after the next block (%d).",
+			endHeight,
+			k.MaxSubscriptionDuration(ctx),
+			nextHeight,
+		)
+	}
+
+	// The gas of every request is charged in the bond denomination, so a deposit without it
+	// could not pay for a single request.
+	bondDenom := k.StakingKeeper.BondDenom(ctx)
+	if !deposit.AmountOf(bondDenom).IsPositive() {
+		return 0, sdkerrors.Wrapf(types.ErrBadDataValue,
+			"AddSubscription: Deposit (%s) must include the bond denomination (%s).",
+			deposit.String(),
+			bondDenom,
+		)
+	}
+
+	err := k.SupplyKeeper.SendCoinsFromAccountToModule(ctx, owner, types.ModuleName, deposit)
+	if err != nil {
+		return 0, err
+	}
+
+	subscriptionID := k.GetNextSubscriptionID(ctx)
+	k.SetSubscription(ctx, subscriptionID, types.NewSubscription(
+		owner,
+		oracleScriptID,
+		calldata,
+		requestedValidatorCount,
+		sufficientValidatorCount,
+		expiration,
+		prepareGas,
+		executeGas,
+		interval,
+		endHeight,
+		deposit,
+		nextHeight,
+	))
+	k.scheduleSubscription(ctx, subscriptionID, nextHeight)
+	return subscriptionID, nil
+}
+
+// SetSubscription saves the given subscription to the store without performing validation.
+func (k Keeper) SetSubscription(ctx sdk.Context, id types.SubscriptionID, subscription types.Subscription) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.SubscriptionStoreKey(id), k.cdc.MustMarshalBinaryBare(subscription))
+}
+
+// GetSubscription returns the entire Subscription metadata struct.
+func (k Keeper) GetSubscription(ctx sdk.Context, id types.SubscriptionID) (types.Subscription, error) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.SubscriptionStoreKey(id))
+	if bz == nil {
+		return types.Subscription{}, sdkerrors.Wrapf(types.ErrItemNotFound,
+			"GetSubscription: Unknown subscription ID %d.",
+			id,
+		)
+	}
+
+	var subscription types.Subscription
+	k.cdc.MustUnmarshalBinaryBare(bz, &subscription)
+	return subscription, nil
+}
+
+// scheduleSubscription adds the subscription to the schedule at the given height.
+func (k Keeper) scheduleSubscription(ctx sdk.Context, id types.SubscriptionID, height int64) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.SubscriptionScheduleStoreKey(height, id), []byte{})
+}
+
+// GetDueSubscriptionIDs returns the IDs of at most limit subscriptions scheduled at or before the
+// current block height, ordered by scheduled height and then by ID.
+func (k Keeper) GetDueSubscriptionIDs(ctx sdk.Context, limit int64) []types.SubscriptionID {
+	store := ctx.KVStore(k.storeKey)
+	iterator := store.Iterator(
+		types.SubscriptionScheduleStoreKeyPrefix,
+		types.SubscriptionScheduleStoreKey(ctx.BlockHeight()+1, 0),
+	)
+	defer iterator.Close()
+
+	var ids []types.SubscriptionID
+	for ; iterator.Valid() && int64(len(ids)) < limit; iterator.Next() {
+		ids = append(ids, types.GetSubscriptionIDFromScheduleKey(iterator.Key()))
+	}
+	return ids
+}
+
+// RescheduleSubscription moves the subscription to the schedule one interval after its current
+// scheduled height.
+func (k Keeper) RescheduleSubscription(ctx sdk.Context, id types.SubscriptionID) error {
+	subscription, err := k.GetSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(types.SubscriptionScheduleStoreKey(subscription.NextHeight, id))
+	subscription.NextHeight += subscription.Interval
+	k.SetSubscription(ctx, id, subscription)
+	k.scheduleSubscription(ctx, id, subscription.NextHeight)
+	return nil
+}
+
+// CloseSubscription refunds the remaining balance of the subscription to its owner and deletes
+// the subscription.
+func (k Keeper) CloseSubscription(ctx sdk.Context, id types.SubscriptionID) error {
+	subscription, err := k.GetSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !subscription.Balance.IsZero() {
+		err = k.SupplyKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, subscription.Owner, subscription.Balance)
+		if err != nil {
+			return err
+		}
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(types.SubscriptionScheduleStoreKey(subscription.NextHeight, id))
+	store.Delete(types.SubscriptionStoreKey(id))
+	return nil
+}
+
+// PaySubscriptionDataSourceFees pays the data source fees of a request made for a subscription
+// out of the balance of the subscription. Data sources owned by the subscription owner are free.
+func (k Keeper) PaySubscriptionDataSourceFees(
+	ctx sdk.Context, requestID types.RequestID, subscriptionID types.SubscriptionID,
+) error {
+	subscription, err := k.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		return err
+	}
+
+	for _, rawDataRequest := range k.GetRawDataRequests(ctx, requestID) {
+		dataSource, err := k.GetDataSource(ctx, rawDataRequest.DataSourceID)
+		if err != nil {
+			return err
+		}
+
+		if dataSource.Owner.Equals(subscription.Owner) || dataSource.Fee.IsZero() {
+			continue
+		}
+
+		balance, negative := subscription.Balance.SafeSub(dataSource.Fee)
+		if negative {
+			return sdkerrors.Wrapf(sdkerrors.ErrInsufficientFunds,
+				"PaySubscriptionDataSourceFees: Balance (%s) of subscription %d is less than the fee (%s).",
+				subscription.Balance.String(),
+				subscriptionID,
+				dataSource.Fee.String(),
+			)
+		}
+		err = k.SupplyKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, dataSource.Owner, dataSource.Fee)
+		if err != nil {
+			return err
+		}
+		subscription.Balance = balance
+	}
+
+	k.SetSubscription(ctx, subscriptionID, subscription)
+	return nil
+}
+
+// ChargeSubscriptionGas pays for the given gas used to make a request for the subscription out of
+// its balance, at SubscriptionGasPrice in the bond denomination, to the fee collector.
+func (k Keeper) ChargeSubscriptionGas(ctx sdk.Context, subscriptionID types.SubscriptionID, gas uint64) error {
+	subscription, err := k.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		return err
+	}
+
+	price := sdk.NewIntFromUint64(k.SubscriptionGasPrice(ctx))
+	cost := sdk.NewCoins(sdk.NewCoin(k.StakingKeeper.BondDenom(ctx), price.Mul(sdk.NewIntFromUint64(gas))))
+	balance, negative := subscription.Balance.SafeSub(cost)
+	if negative {
+		return sdkerrors.Wrapf(sdkerrors.ErrInsufficientFunds,
+			"ChargeSubscriptionGas: Balance (%s) of subscription %d is less than the gas cost (%s).",
+			subscription.Balance.String(),
+			subscriptionID,
+			cost.String(),
+		)
+	}
+	err = k.SupplyKeeper.SendCoinsFromModuleToModule(ctx, types.ModuleName, k.feeCollectorName, cost)
+	if err != nil {
+		return err
+	}
+
+	subscription.Balance = balance
+	k.SetSubscription(ctx, subscriptionID, subscription)
+	return nil
+}
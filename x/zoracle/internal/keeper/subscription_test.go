@@ -0,0 +1,190 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+// addTestSubscription funds the owner with the deposit and adds a subscription to oracle script
+// 1 requested every interval blocks until the given end height.
+func addTestSubscription(
+	t *testing.T, ctx sdk.Context, keeper Keeper, owner sdk.AccAddress, interval, endHeight int64,
+	deposit sdk.Coins,
+) types.SubscriptionID {
+	keeper.SetOracleScript(ctx, 1, types.NewOracleScript(owner, "script", "description", []byte("code")))
+	FundTestAccount(ctx, keeper, owner, deposit)
+	id, err := keeper.AddSubscription(ctx, owner, 1, []byte("calldata"), 1, 1, 10, 1000, 1000, interval, endHeight, deposit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func TestAddSubscriptionValidatesDepositAndDuration(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	owner := sdk.AccAddress([]byte("owner_______________"))
+	keeper.SetOracleScript(ctx, 1, types.NewOracleScript(owner, "script", "description", []byte("code")))
+	bondDenom := keeper.StakingKeeper.BondDenom(ctx)
+	FundTestAccount(ctx, keeper, owner, sdk.NewCoins(sdk.NewInt64Coin(bondDenom, 100), sdk.NewInt64Coin("other", 100)))
+	keeper.SetMaxSubscriptionDuration(ctx, 50)
+
+	cases := map[string]struct {
+		endHeight int64
+		deposit   sdk.Coins
+	}{
+		"other denomination only": {10, sdk.NewCoins(sdk.NewInt64Coin("other", 10))},
+		"empty deposit":           {10, sdk.Coins{}},
+		"over maximum duration":   {ctx.BlockHeight() + 52, sdk.NewCoins(sdk.NewInt64Coin(bondDenom, 10))},
+	}
+	for name, c := range cases {
+		_, err := keeper.AddSubscription(ctx, owner, 1, []byte("calldata"), 1, 1, 10, 1000, 1000, 1, c.endHeight, c.deposit)
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	deposit := sdk.NewCoins(sdk.NewInt64Coin(bondDenom, 10))
+	if _, err := keeper.AddSubscription(ctx, owner, 1, []byte("calldata"), 1, 1, 10, 1000, 1000, 1, ctx.BlockHeight()+51, deposit); err != nil {
+		t.Fatalf("subscription of the maximum duration: %v", err)
+	}
+	if balance := keeper.CoinKeeper.GetBalance(ctx, owner, bondDenom); !balance.Equal(sdk.NewInt64Coin(bondDenom, 90)) {
+		t.Errorf("owner balance %s, expected the deposit to be escrowed", balance)
+	}
+}
+
+func TestRescheduleSubscription(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	bondDenom := keeper.StakingKeeper.BondDenom(ctx)
+	id := addTestSubscription(
+		t, ctx, keeper, sdk.AccAddress([]byte("owner_______________")), 5, 100,
+		sdk.NewCoins(sdk.NewInt64Coin(bondDenom, 10)),
+	)
+
+	if ids := keeper.GetDueSubscriptionIDs(ctx, 10); len(ids) != 0 {
+		t.Fatalf("due subscriptions %v before the first request height", ids)
+	}
+	ctx = ctx.WithBlockHeight(2)
+	if ids := keeper.GetDueSubscriptionIDs(ctx, 10); len(ids) != 1 || ids[0] != id {
+		t.Fatalf("due subscriptions %v, expected [%d]", ids, id)
+	}
+
+	if err := keeper.RescheduleSubscription(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	subscription, _ := keeper.GetSubscription(ctx, id)
+	if subscription.NextHeight != 7 {
+		t.Errorf("next height %d, expected 7", subscription.NextHeight)
+	}
+	for height, due := range map[int64]bool{2: false, 6: false, 7: true} {
+		ids := keeper.GetDueSubscriptionIDs(ctx.WithBlockHeight(height), 10)
+		if (len(ids) == 1) != due {
+			t.Errorf("height %d: due subscriptions %v, expected due %t", height, ids, due)
+		}
+	}
+}
+
+func TestGetDueSubscriptionIDsLimit(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	bondDenom := keeper.StakingKeeper.BondDenom(ctx)
+	for i := 0; i < 3; i++ {
+		addTestSubscription(
+			t, ctx, keeper, sdk.AccAddress([]byte("owner_______________")), 5, 100,
+			sdk.NewCoins(sdk.NewInt64Coin(bondDenom, 10)),
+		)
+	}
+
+	ctx = ctx.WithBlockHeight(2)
+	if ids := keeper.GetDueSubscriptionIDs(ctx, 2); len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+		t.Errorf("due subscriptions %v, expected [1 2]", ids)
+	}
+	if ids := keeper.GetDueSubscriptionIDs(ctx, 10); len(ids) != 3 {
+		t.Errorf("due subscriptions %v, expected all 3", ids)
+	}
+}
+
+func TestChargeSubscriptionGas(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	bondDenom := keeper.StakingKeeper.BondDenom(ctx)
+	keeper.SetSubscriptionGasPrice(ctx, 2)
+	id := addTestSubscription(
+		t, ctx, keeper, sdk.AccAddress([]byte("owner_______________")), 5, 100,
+		sdk.NewCoins(sdk.NewInt64Coin(bondDenom, 100)),
+	)
+
+	if err := keeper.ChargeSubscriptionGas(ctx, id, 30); err != nil {
+		t.Fatal(err)
+	}
+	subscription, _ := keeper.GetSubscription(ctx, id)
+	if !subscription.Balance.IsEqual(sdk.NewCoins(sdk.NewInt64Coin(bondDenom, 40))) {
+		t.Errorf("balance %s, expected 40%s", subscription.Balance, bondDenom)
+	}
+	feeCollector := keeper.SupplyKeeper.GetModuleAddress(auth.FeeCollectorName)
+	if balance := keeper.CoinKeeper.GetBalance(ctx, feeCollector, bondDenom); !balance.Equal(sdk.NewInt64Coin(bondDenom, 60)) {
+		t.Errorf("fee collector balance %s, expected 60%s", balance, bondDenom)
+	}
+
+	if err := keeper.ChargeSubscriptionGas(ctx, id, 21); !sdkerrors.ErrInsufficientFunds.Is(err) {
+		t.Errorf("charging more than the balance: got %v, expected insufficient funds", err)
+	}
+	subscription, _ = keeper.GetSubscription(ctx, id)
+	if !subscription.Balance.IsEqual(sdk.NewCoins(sdk.NewInt64Coin(bondDenom, 40))) {
+		t.Errorf("balance %s after a failed charge, expected it unchanged", subscription.Balance)
+	}
+}
+
+func TestPaySubscriptionDataSourceFees(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	bondDenom := keeper.StakingKeeper.BondDenom(ctx)
+	owner := sdk.AccAddress([]byte("owner_______________"))
+	provider := sdk.AccAddress([]byte("provider____________"))
+	id := addTestSubscription(t, ctx, keeper, owner, 5, 100, sdk.NewCoins(sdk.NewInt64Coin(bondDenom, 25)))
+
+	setRawRequestTestRequest(ctx, keeper, 1, []sdk.ValAddress{sdk.ValAddress([]byte("validator"))})
+	fee := sdk.NewCoins(sdk.NewInt64Coin(bondDenom, 10))
+	keeper.SetDataSource(ctx, 1, types.NewDataSource(provider, "paid", "description", fee, []byte("executable")))
+	keeper.SetDataSource(ctx, 2, types.NewDataSource(owner, "own", "description", fee, []byte("executable")))
+	keeper.SetDataSource(ctx, 3, types.NewDataSource(provider, "free", "description", sdk.Coins{}, []byte("executable")))
+	for externalID, dataSourceID := range map[types.ExternalID]types.DataSourceID{1: 1, 2: 2, 3: 3, 4: 1} {
+		keeper.SetRawDataRequest(ctx, 1, externalID, types.NewRawDataRequest(dataSourceID, []byte("calldata")))
+	}
+
+	// Only the two requests to the paid data source of someone else are charged.
+	if err := keeper.PaySubscriptionDataSourceFees(ctx, 1, id); err != nil {
+		t.Fatal(err)
+	}
+	subscription, _ := keeper.GetSubscription(ctx, id)
+	if !subscription.Balance.IsEqual(sdk.NewCoins(sdk.NewInt64Coin(bondDenom, 5))) {
+		t.Errorf("balance %s, expected 5%s", subscription.Balance, bondDenom)
+	}
+	if balance := keeper.CoinKeeper.GetBalance(ctx, provider, bondDenom); !balance.Equal(sdk.NewInt64Coin(bondDenom, 20)) {
+		t.Errorf("provider balance %s, expected 20%s", balance, bondDenom)
+	}
+
+	if err := keeper.PaySubscriptionDataSourceFees(ctx, 1, id); !sdkerrors.ErrInsufficientFunds.Is(err) {
+		t.Errorf("paying more than the balance: got %v, expected insufficient funds", err)
+	}
+}
+
+func TestCloseSubscriptionRefundsBalance(t *testing.T) {
+	ctx, keeper := CreateTestInput()
+	bondDenom := keeper.StakingKeeper.BondDenom(ctx)
+	owner := sdk.AccAddress([]byte("owner_______________"))
+	id := addTestSubscription(t, ctx, keeper, owner, 5, 100, sdk.NewCoins(sdk.NewInt64Coin(bondDenom, 25)))
+
+	if err := keeper.CloseSubscription(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if balance := keeper.CoinKeeper.GetBalance(ctx, owner, bondDenom); !balance.Equal(sdk.NewInt64Coin(bondDenom, 25)) {
+		t.Errorf("owner balance %s, expected the deposit back", balance)
+	}
+	if _, err := keeper.GetSubscription(ctx, id); err == nil {
+		t.Error("expected the subscription to be deleted")
+	}
+	if ids := keeper.GetDueSubscriptionIDs(ctx.WithBlockHeight(2), 10); len(ids) != 0 {
+		t.Errorf("due subscriptions %v, expected the schedule to be cleared", ids)
+	}
+}
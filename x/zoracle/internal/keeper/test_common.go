@@ -0,0 +1,109 @@
+package keeper
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	codecstd "github.com/cosmos/cosmos-sdk/codec/std"
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/params"
+	"github.com/cosmos/cosmos-sdk/x/staking"
+	"github.com/cosmos/cosmos-sdk/x/supply"
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/libs/log"
+	dbm "github.com/tendermint/tm-db"
+)
+
+// CreateTestInput returns a context and a zoracle keeper backed by an in-memory store, with real
+// account, bank, supply and staking keepers and the default parameters. The keeper has no channel
+// keeper, so it cannot send packets.
+func CreateTestInput() (sdk.Context, Keeper) {
+	ctx, keeper := createTestInputWithoutParams()
+	keeper.SetParams(ctx, types.DefaultParams())
+	return ctx, keeper
+}
+
+// createTestInputWithoutParams is like CreateTestInput, but leaves the zoracle parameters unset.
+func createTestInputWithoutParams() (sdk.Context, Keeper) {
+	keyAcc := sdk.NewKVStoreKey(auth.StoreKey)
+	keyBank := sdk.NewKVStoreKey(bank.StoreKey)
+	keySupply := sdk.NewKVStoreKey(supply.StoreKey)
+	keyStaking := sdk.NewKVStoreKey(staking.StoreKey)
+	keyZoracle := sdk.NewKVStoreKey(types.StoreKey)
+	keyParams := sdk.NewKVStoreKey(params.StoreKey)
+	tkeyParams := sdk.NewTransientStoreKey(params.TStoreKey)
+
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	for _, key := range []*sdk.KVStoreKey{keyAcc, keyBank, keySupply, keyStaking, keyZoracle, keyParams} {
+		ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, db)
+	}
+	ms.MountStoreWithDB(tkeyParams, sdk.StoreTypeTransient, db)
+	if err := ms.LoadLatestVersion(); err != nil {
+		panic(err)
+	}
+	ctx := sdk.NewContext(ms, abci.Header{Height: 1}, false, log.NewNopLogger())
+
+	cdc := codec.New()
+	auth.RegisterCodec(cdc)
+	bank.RegisterCodec(cdc)
+	supply.RegisterCodec(cdc)
+	staking.RegisterCodec(cdc)
+	types.RegisterCodec(cdc)
+	sdk.RegisterCodec(cdc)
+	codec.RegisterCrypto(cdc)
+	appCodec := codecstd.NewAppCodec(cdc)
+
+	maccPerms := map[string][]string{
+		auth.FeeCollectorName:          nil,
+		staking.BondedPoolName:         {supply.Burner, supply.Staking},
+		staking.NotBondedPoolName:      {supply.Burner, supply.Staking},
+		types.ModuleName:               nil,
+		types.ReferenceDataAccountName: nil,
+	}
+	paramsKeeper := params.NewKeeper(appCodec, keyParams, tkeyParams)
+	accountKeeper := auth.NewAccountKeeper(
+		appCodec, keyAcc, paramsKeeper.Subspace(auth.DefaultParamspace), auth.ProtoBaseAccount,
+	)
+	bankKeeper := bank.NewBaseKeeper(
+		appCodec, keyBank, accountKeeper, paramsKeeper.Subspace(bank.DefaultParamspace), map[string]bool{},
+	)
+	supplyKeeper := supply.NewKeeper(appCodec, keySupply, accountKeeper, bankKeeper, maccPerms)
+	stakingKeeper := staking.NewKeeper(
+		appCodec, keyStaking, bankKeeper, supplyKeeper, paramsKeeper.Subspace(staking.DefaultParamspace),
+	)
+	stakingKeeper.SetParams(ctx, staking.DefaultParams())
+
+	keeper := NewKeeper(
+		cdc, keyZoracle, bankKeeper, supplyKeeper, stakingKeeper, nil,
+		paramsKeeper.Subspace(types.DefaultParamspace), auth.FeeCollectorName,
+	)
+	return ctx, keeper
+}
+
+// CreateTestValidators adds the given number of bonded validators with decreasing voting power
+// to the staking keeper, and returns their addresses in order of voting power.
+func CreateTestValidators(ctx sdk.Context, keeper Keeper, count int) []sdk.ValAddress {
+	addresses := make([]sdk.ValAddress, count)
+	for i := 0; i < count; i++ {
+		pubKey := ed25519.GenPrivKeyFromSecret([]byte{byte(i)}).PubKey()
+		addresses[i] = sdk.ValAddress(pubKey.Address())
+		validator := staking.NewValidator(addresses[i], pubKey, staking.Description{})
+		validator.Status = sdk.Bonded
+		validator.Tokens = sdk.TokensFromConsensusPower(int64(count - i))
+		validator.DelegatorShares = validator.Tokens.ToDec()
+		keeper.StakingKeeper.SetValidator(ctx, validator)
+		keeper.StakingKeeper.SetValidatorByPowerIndex(ctx, validator)
+	}
+	return addresses
+}
+
+// FundTestAccount sets the balance of the given account, creating the account if needed.
+func FundTestAccount(ctx sdk.Context, keeper Keeper, address sdk.AccAddress, coins sdk.Coins) {
+	if err := keeper.CoinKeeper.SetBalances(ctx, address, coins); err != nil {
+		panic(err)
+	}
+}
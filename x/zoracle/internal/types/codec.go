@@ -23,5 +23,7 @@ func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgEditDataSource{}, "zoracle/EditDataSource", nil)
 	cdc.RegisterConcrete(MsgCreateOracleScript{}, "zoracle/CreateOracleScript", nil)
 	cdc.RegisterConcrete(MsgEditOracleScript{}, "zoracle/EditOracleScript", nil)
+	cdc.RegisterConcrete(MsgSubscribe{}, "zoracle/Subscribe", nil)
+	cdc.RegisterConcrete(MsgCancelRequest{}, "zoracle/CancelRequest", nil)
 	cdc.RegisterConcrete(OraclePacketData{}, "zoracle/OraclePacketData", nil)
 }
@@ -10,9 +10,20 @@ const (
 	EventTypeReport              = "report"
 	EventTypeAddOracleAddress    = "add_oracle_address"
 	EventTypeRemoveOracleAddress = "remove_oracle_address"
+	EventTypeRequestResolved     = "request_resolved"
+	EventTypeSubscribe           = "subscribe"
+	EventTypeSubscriptionClosed  = "subscription_closed"
+	EventTypeCancelRequest       = "cancel_request"
 
-	AttributeKeyID        = "id"
-	AttributeKeyRequestID = "request_id"
-	AttributeKeyValidator = "validator"
-	AttributeKeyReporter  = "reporter"
+	AttributeKeyID             = "id"
+	AttributeKeyRequestID      = "request_id"
+	AttributeKeyValidator      = "validator"
+	AttributeKeyReporter       = "reporter"
+	AttributeKeyResolveStatus  = "resolve_status"
+	AttributeKeyFailureReason  = "failure_reason"
+	AttributeKeyGasUsed        = "gas_used"
+	AttributeKeyResultHash     = "result_hash"
+	AttributeKeyPacketSequence = "packet_sequence"
+	AttributeKeySubscriptionID = "subscription_id"
+	AttributeKeyReason         = "reason"
 )
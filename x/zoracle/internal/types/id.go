@@ -4,3 +4,4 @@ type RequestID int64
 type OracleScriptID int64
 type ExternalID int64
 type DataSourceID int64
+type SubscriptionID int64
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"crypto/sha256"
 	"encoding/binary"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -20,15 +21,31 @@ var (
 	// RequestsCountStoreKey is a key that help getting to current requests count state variable
 	RequestsCountStoreKey = append(GlobalStoreKeyPrefix, []byte("RequestsCount")...)
 
-	// PendingResolveListStoreKey is a key that help getting pending request
+	// PendingResolveListStoreKey is the legacy key of the whole pending request list, which is only
+	// read by the store migration to the indexed pending keys.
 	PendingResolveListStoreKey = append(GlobalStoreKeyPrefix, []byte("PendingList")...)
 
+	// PendingSequenceStoreKey is a key that keeps the current enqueue sequence of pending requests.
+	PendingSequenceStoreKey = append(GlobalStoreKeyPrefix, []byte("PendingSequence")...)
+
+	// PruneCursorStoreKey is a key that keeps the ID of the next request to consider for pruning.
+	PruneCursorStoreKey = append(GlobalStoreKeyPrefix, []byte("PruneCursor")...)
+
 	// DataSourceCountStoreKey is a key that keeps the current data source count state variable.
 	DataSourceCountStoreKey = append(GlobalStoreKeyPrefix, []byte("DataSourceCount")...)
 
 	// OracleScriptCountStoreKey is a key that keeps the current oracle script count state variable.
 	OracleScriptCountStoreKey = append(GlobalStoreKeyPrefix, []byte("OracleScriptCount")...)
 
+	// SubscriptionCountStoreKey is a key that keeps the current subscription count state variable.
+	SubscriptionCountStoreKey = append(GlobalStoreKeyPrefix, []byte("SubscriptionCount")...)
+
+	// GasScheduleCountStoreKey is a key that keeps the number of gas schedules ever recorded.
+	GasScheduleCountStoreKey = append(GlobalStoreKeyPrefix, []byte("GasScheduleCount")...)
+
+	// CurrentGasScheduleVersionStoreKey is a key that keeps the version of the gas schedule parameter.
+	CurrentGasScheduleVersionStoreKey = append(GlobalStoreKeyPrefix, []byte("CurrentGasScheduleVersion")...)
+
 	// ========================================================================
 
 	// RequestStoreKeyPrefix is a prefix for request store
@@ -51,8 +68,83 @@ var (
 
 	// ReporterStoreKeyPrefix is a prefix for reporter store.
 	ReporterStoreKeyPrefix = []byte{0x06}
+
+	// GasScheduleStoreKeyPrefix is a prefix for storing every gas schedule version ever used.
+	GasScheduleStoreKeyPrefix = []byte{0x07}
+
+	// PendingRequestStoreKeyPrefix is a prefix for pending requests ordered by enqueue sequence.
+	PendingRequestStoreKeyPrefix = []byte{0x08}
+
+	// PendingRequestIndexStoreKeyPrefix is a prefix for the enqueue sequence of each pending request.
+	PendingRequestIndexStoreKeyPrefix = []byte{0x09}
+
+	// RawDataRequestCountStoreKeyPrefix is a prefix for the number of raw data requests of each request.
+	RawDataRequestCountStoreKeyPrefix = []byte{0x0a}
+
+	// RawDataRequestExternalIDStoreKeyPrefix is a prefix for the external IDs of each request, in ascending order.
+	RawDataRequestExternalIDStoreKeyPrefix = []byte{0x0b}
+
+	// LatestResultStoreKeyPrefix is a prefix for the latest request with a result of each oracle script and calldata.
+	LatestResultStoreKeyPrefix = []byte{0x0c}
+
+	// ReportCommitmentStoreKeyPrefix is a prefix for the commitment that replaces the raw reports of a resolved request.
+	ReportCommitmentStoreKeyPrefix = []byte{0x0d}
+
+	// ReferenceSymbolStoreKeyPrefix is a prefix for the height of the last request made for each reference symbol.
+	ReferenceSymbolStoreKeyPrefix = []byte{0x0e}
+
+	// SubscriptionStoreKeyPrefix is a prefix for subscription store.
+	SubscriptionStoreKeyPrefix = []byte{0x0f}
+
+	// SubscriptionScheduleStoreKeyPrefix is a prefix for subscriptions ordered by the height of their next request.
+	SubscriptionScheduleStoreKeyPrefix = []byte{0x10}
+
+	// RequesterRequestStoreKeyPrefix is a prefix for the IDs of the requests made by each requester.
+	RequesterRequestStoreKeyPrefix = []byte{0x11}
+
+	// OracleScriptRequestStoreKeyPrefix is a prefix for the IDs of the requests to each oracle script.
+	OracleScriptRequestStoreKeyPrefix = []byte{0x12}
+
+	// ResolveStatusRequestStoreKeyPrefix is a prefix for the IDs of the requests in each resolve status.
+	ResolveStatusRequestStoreKeyPrefix = []byte{0x13}
+
+	// ValidatorRequestStoreKeyPrefix is a prefix for the IDs of the requests assigned to each validator.
+	ValidatorRequestStoreKeyPrefix = []byte{0x14}
+
+	// SourceChannelRequestStoreKeyPrefix is a prefix for the IDs of the requests from each IBC channel.
+	SourceChannelRequestStoreKeyPrefix = []byte{0x15}
+
+	// ValidatorAssignmentStoreKeyPrefix is a prefix for the IDs of the open requests that each
+	// validator has yet to report to.
+	ValidatorAssignmentStoreKeyPrefix = []byte{0x16}
+
+	// GasScheduleVersionStoreKeyPrefix is a prefix for the version of each recorded gas schedule by its hash.
+	GasScheduleVersionStoreKeyPrefix = []byte{0x17}
+
+	// RequestExpirationStoreKeyPrefix is a prefix for the IDs of the open requests ordered by expiration height.
+	RequestExpirationStoreKeyPrefix = []byte{0x18}
 )
 
+// GasScheduleStoreKey is a function to generate key for each gas schedule version in store
+func GasScheduleStoreKey(version uint64) []byte {
+	return append(GasScheduleStoreKeyPrefix, int64ToBytes(int64(version))...)
+}
+
+// GasScheduleVersionStoreKey is a function to generate key for the version of a gas schedule hash in store
+func GasScheduleVersionStoreKey(hash [sha256.Size]byte) []byte {
+	return append(GasScheduleVersionStoreKeyPrefix, hash[:]...)
+}
+
+// PendingRequestStoreKey is a function to generate key for each pending request entry in store
+func PendingRequestStoreKey(sequence uint64) []byte {
+	return append(PendingRequestStoreKeyPrefix, int64ToBytes(int64(sequence))...)
+}
+
+// PendingRequestIndexStoreKey is a function to generate key for the pending entry of a request in store
+func PendingRequestIndexStoreKey(requestID RequestID) []byte {
+	return append(PendingRequestIndexStoreKeyPrefix, int64ToBytes(int64(requestID))...)
+}
+
 // RequestStoreKey is a function to generate key for each request in store
 func RequestStoreKey(requestID RequestID) []byte {
 	return append(RequestStoreKeyPrefix, int64ToBytes(int64(requestID))...)
@@ -66,6 +158,129 @@ func ResultStoreKey(requestID RequestID, oracleScriptID OracleScriptID, calldata
 	return buf
 }
 
+// LatestResultStoreKey is a function to generate key for the latest result of each oracle script and
+// calldata in store. The calldata is hashed to keep the key size bounded.
+func LatestResultStoreKey(oracleScriptID OracleScriptID, calldata []byte) []byte {
+	calldataHash := sha256.Sum256(calldata)
+	buf := append(LatestResultStoreKeyPrefix, int64ToBytes(int64(oracleScriptID))...)
+	buf = append(buf, calldataHash[:]...)
+	return buf
+}
+
+// ReportCommitmentStoreKey is a function to generate key for the report commitment of each request in store
+func ReportCommitmentStoreKey(requestID RequestID) []byte {
+	return append(ReportCommitmentStoreKeyPrefix, int64ToBytes(int64(requestID))...)
+}
+
+// ReferenceSymbolStoreKey is a function to generate key for the last request height of each reference symbol in store
+func ReferenceSymbolStoreKey(symbol string) []byte {
+	return append(ReferenceSymbolStoreKeyPrefix, []byte(symbol)...)
+}
+
+// SubscriptionStoreKey is a function to generate key for each subscription in store
+func SubscriptionStoreKey(subscriptionID SubscriptionID) []byte {
+	return append(SubscriptionStoreKeyPrefix, int64ToBytes(int64(subscriptionID))...)
+}
+
+// SubscriptionScheduleStoreKey is a function to generate key for the next request of each subscription in store
+func SubscriptionScheduleStoreKey(height int64, subscriptionID SubscriptionID) []byte {
+	buf := append(SubscriptionScheduleStoreKeyPrefix, int64ToBytes(height)...)
+	buf = append(buf, int64ToBytes(int64(subscriptionID))...)
+	return buf
+}
+
+// GetSubscriptionIDFromScheduleKey is a function to get the subscription ID from a subscription schedule key
+func GetSubscriptionIDFromScheduleKey(key []byte) SubscriptionID {
+	return SubscriptionID(binary.BigEndian.Uint64(key[len(SubscriptionScheduleStoreKeyPrefix)+8:]))
+}
+
+// RequestExpirationStoreKey is a function to generate key for the expiration of each open request in store
+func RequestExpirationStoreKey(expirationHeight int64, requestID RequestID) []byte {
+	buf := append(RequestExpirationStoreKeyPrefix, int64ToBytes(expirationHeight)...)
+	buf = append(buf, int64ToBytes(int64(requestID))...)
+	return buf
+}
+
+// GetRequestIDFromExpirationKey is a function to get the request ID from a request expiration key
+func GetRequestIDFromExpirationKey(key []byte) RequestID {
+	return RequestID(binary.BigEndian.Uint64(key[len(RequestExpirationStoreKeyPrefix)+8:]))
+}
+
+// RequesterRequestsStoreKeyPrefix is a function to generate the prefix of the requests of a requester in store.
+// The address is length-prefixed so that no address is a prefix of another.
+func RequesterRequestsStoreKeyPrefix(requester sdk.AccAddress) []byte {
+	buf := append(RequesterRequestStoreKeyPrefix, byte(len(requester)))
+	return append(buf, requester...)
+}
+
+// RequesterRequestStoreKey is a function to generate key for each request of a requester in store
+func RequesterRequestStoreKey(requester sdk.AccAddress, requestID RequestID) []byte {
+	return append(RequesterRequestsStoreKeyPrefix(requester), int64ToBytes(int64(requestID))...)
+}
+
+// OracleScriptRequestsStoreKeyPrefix is a function to generate the prefix of the requests to an oracle script in store.
+func OracleScriptRequestsStoreKeyPrefix(oracleScriptID OracleScriptID) []byte {
+	return append(OracleScriptRequestStoreKeyPrefix, int64ToBytes(int64(oracleScriptID))...)
+}
+
+// OracleScriptRequestStoreKey is a function to generate key for each request to an oracle script in store
+func OracleScriptRequestStoreKey(oracleScriptID OracleScriptID, requestID RequestID) []byte {
+	return append(OracleScriptRequestsStoreKeyPrefix(oracleScriptID), int64ToBytes(int64(requestID))...)
+}
+
+// ResolveStatusRequestsStoreKeyPrefix is a function to generate the prefix of the requests in a resolve status in store.
+func ResolveStatusRequestsStoreKeyPrefix(status ResolveStatus) []byte {
+	return append(ResolveStatusRequestStoreKeyPrefix, byte(status))
+}
+
+// ResolveStatusRequestStoreKey is a function to generate key for each request in a resolve status in store
+func ResolveStatusRequestStoreKey(status ResolveStatus, requestID RequestID) []byte {
+	return append(ResolveStatusRequestsStoreKeyPrefix(status), int64ToBytes(int64(requestID))...)
+}
+
+// ValidatorRequestsStoreKeyPrefix is a function to generate the prefix of the requests assigned to a validator in store.
+func ValidatorRequestsStoreKeyPrefix(validator sdk.ValAddress) []byte {
+	buf := append(ValidatorRequestStoreKeyPrefix, byte(len(validator)))
+	return append(buf, validator...)
+}
+
+// ValidatorRequestStoreKey is a function to generate key for each request assigned to a validator in store
+func ValidatorRequestStoreKey(validator sdk.ValAddress, requestID RequestID) []byte {
+	return append(ValidatorRequestsStoreKeyPrefix(validator), int64ToBytes(int64(requestID))...)
+}
+
+// ValidatorAssignmentsStoreKeyPrefix is a function to generate the prefix of the open assignments of a
+// validator in store.
+func ValidatorAssignmentsStoreKeyPrefix(validator sdk.ValAddress) []byte {
+	buf := append(ValidatorAssignmentStoreKeyPrefix, byte(len(validator)))
+	return append(buf, validator...)
+}
+
+// ValidatorAssignmentStoreKey is a function to generate key for each open assignment of a validator in store
+func ValidatorAssignmentStoreKey(validator sdk.ValAddress, requestID RequestID) []byte {
+	return append(ValidatorAssignmentsStoreKeyPrefix(validator), int64ToBytes(int64(requestID))...)
+}
+
+// SourceChannelRequestsStoreKeyPrefix is a function to generate the prefix of the requests from an IBC
+// port and channel in store.
+func SourceChannelRequestsStoreKeyPrefix(sourcePort, sourceChannel string) []byte {
+	buf := append(SourceChannelRequestStoreKeyPrefix, byte(len(sourcePort)))
+	buf = append(buf, sourcePort...)
+	buf = append(buf, byte(len(sourceChannel)))
+	return append(buf, sourceChannel...)
+}
+
+// SourceChannelRequestStoreKey is a function to generate key for each request from an IBC port and
+// channel in store
+func SourceChannelRequestStoreKey(sourcePort, sourceChannel string, requestID RequestID) []byte {
+	return append(SourceChannelRequestsStoreKeyPrefix(sourcePort, sourceChannel), int64ToBytes(int64(requestID))...)
+}
+
+// GetRequestIDFromIndexKey is a function to get the request id from an index key that ends with it.
+func GetRequestIDFromIndexKey(key []byte) RequestID {
+	return RequestID(binary.BigEndian.Uint64(key[len(key)-8:]))
+}
+
 // RawDataRequestStoreKey is a function to generate key for each raw data request in store
 func RawDataRequestStoreKey(requestID RequestID, externalID ExternalID) []byte {
 	buf := append(RawDataRequestStoreKeyPrefix, int64ToBytes(int64(requestID))...)
@@ -73,6 +288,26 @@ func RawDataRequestStoreKey(requestID RequestID, externalID ExternalID) []byte {
 	return buf
 }
 
+// RawDataRequestCountStoreKey is a function to generate key for the raw data request count of each request in store
+func RawDataRequestCountStoreKey(requestID RequestID) []byte {
+	return append(RawDataRequestCountStoreKeyPrefix, int64ToBytes(int64(requestID))...)
+}
+
+// RawDataRequestExternalIDStoreKey is a function to generate key for each external ID of a request in store.
+// The external ID is encoded with its sign bit flipped, so that iterating the keys of a request
+// returns its external IDs in ascending order, negative ones included.
+func RawDataRequestExternalIDStoreKey(requestID RequestID, externalID ExternalID) []byte {
+	buf := append(RawDataRequestExternalIDStoreKeyPrefix, int64ToBytes(int64(requestID))...)
+	return append(buf, sdk.Uint64ToBigEndian(uint64(externalID)^(1<<63))...)
+}
+
+// GetExternalIDFromRawDataRequestExternalIDKey is a function to get external id from raw data request external id key.
+func GetExternalIDFromRawDataRequestExternalIDKey(key []byte) ExternalID {
+	prefixLength := len(RawDataRequestExternalIDStoreKeyPrefix)
+	externalIDBytes := key[prefixLength+8 : prefixLength+16]
+	return ExternalID(binary.BigEndian.Uint64(externalIDBytes) ^ (1 << 63))
+}
+
 // RawDataReportStoreKey is a function to generate key for each raw data report in store.
 func RawDataReportStoreKey(requestID RequestID, externalID ExternalID, validatorAddress sdk.ValAddress) []byte {
 	buf := append(RawDataReportStoreKeyPrefix, int64ToBytes(int64(requestID))...)
@@ -103,6 +338,22 @@ func GetIteratorPrefix(prefix []byte, requestID RequestID) []byte {
 	return append(prefix, int64ToBytes(int64(requestID))...)
 }
 
+// GetRequestIDOracleScriptIDAndCalldataFromResultKey is a function to get request id, oracle script id and
+// calldata from result key.
+func GetRequestIDOracleScriptIDAndCalldataFromResultKey(key []byte) (RequestID, OracleScriptID, []byte) {
+	prefixLength := len(ResultStoreKeyPrefix)
+	requestID := RequestID(binary.BigEndian.Uint64(key[prefixLength : prefixLength+8]))
+	oracleScriptID := OracleScriptID(binary.BigEndian.Uint64(key[prefixLength+8 : prefixLength+16]))
+	return requestID, oracleScriptID, key[prefixLength+16:]
+}
+
+// GetRequestIDFromRawDataRequestKey is a function to get request id from raw data request key.
+func GetRequestIDFromRawDataRequestKey(key []byte) RequestID {
+	prefixLength := len(RawDataRequestStoreKeyPrefix)
+	requestIDBytes := key[prefixLength : prefixLength+8]
+	return RequestID(binary.BigEndian.Uint64(requestIDBytes))
+}
+
 // GetExternalIDFromRawDataRequestKey is a function to get external id from raw data request key.
 func GetExternalIDFromRawDataRequestKey(key []byte) ExternalID {
 	prefixLength := len(RawDataRequestStoreKeyPrefix)
@@ -20,6 +20,7 @@ type MsgRequestData struct {
 	Sender                   sdk.AccAddress `json:"sender"`
 	SourcePort               string         `json:"source_port" yaml:"source_port"`
 	SourceChannel            string         `json:"source_channel" yaml:"source_channel"`
+	PriorityFee              sdk.Coins      `json:"priorityFee"`
 }
 
 // NewMsgRequestData creates a new MsgRequestData instance.
@@ -34,6 +35,7 @@ func NewMsgRequestData(
 	sender sdk.AccAddress,
 	sourcePort string,
 	sourceChannel string,
+	priorityFee sdk.Coins,
 ) MsgRequestData {
 	return MsgRequestData{
 		OracleScriptID:           oracleScriptID,
@@ -46,6 +48,7 @@ func NewMsgRequestData(
 		Sender:                   sender,
 		SourcePort:               sourcePort,
 		SourceChannel:            sourceChannel,
+		PriorityFee:              priorityFee,
 	}
 }
 
@@ -106,6 +109,20 @@ func (msg MsgRequestData) ValidateBasic() error {
 			msg.ExecuteGas,
 		)
 	}
+	if !msg.PriorityFee.IsValid() {
+		return sdkerrors.Wrapf(
+			ErrInvalidBasicMsg,
+			"MsgRequestData: Priority fee (%s) is invalid.",
+			msg.PriorityFee.String(),
+		)
+	}
+	if len(msg.PriorityFee) > 1 {
+		return sdkerrors.Wrapf(
+			ErrInvalidBasicMsg,
+			"MsgRequestData: Priority fee (%s) must be paid in the bond denomination only.",
+			msg.PriorityFee.String(),
+		)
+	}
 	return nil
 }
 
@@ -597,3 +614,191 @@ func (msg MsgRemoveOracleAdderess) GetSignBytes() []byte {
 	bz := ModuleCdc.MustMarshalJSON(msg)
 	return sdk.MustSortJSON(bz)
 }
+
+// MsgSubscribe is a message for requesting an oracle script repeatedly at a fixed interval,
+// paid for by a prepaid deposit.
+type MsgSubscribe struct {
+	OracleScriptID           OracleScriptID `json:"oracleScriptID"`
+	Calldata                 []byte         `json:"calldata"`
+	RequestedValidatorCount  int64          `json:"requestedValidatorCount"`
+	SufficientValidatorCount int64          `json:"sufficientValidatorCount"`
+	Expiration               int64          `json:"expiration"`
+	PrepareGas               uint64         `json:"prepareGas"`
+	ExecuteGas               uint64         `json:"executeGas"`
+	Interval                 int64          `json:"interval"`
+	EndHeight                int64          `json:"endHeight"`
+	Deposit                  sdk.Coins      `json:"deposit"`
+	Sender                   sdk.AccAddress `json:"sender"`
+}
+
+// NewMsgSubscribe creates a new MsgSubscribe instance.
+func NewMsgSubscribe(
+	oracleScriptID OracleScriptID,
+	calldata []byte,
+	requestedValidatorCount int64,
+	sufficientValidatorCount int64,
+	expiration int64,
+	prepareGas uint64,
+	executeGas uint64,
+	interval int64,
+	endHeight int64,
+	deposit sdk.Coins,
+	sender sdk.AccAddress,
+) MsgSubscribe {
+	return MsgSubscribe{
+		OracleScriptID:           oracleScriptID,
+		Calldata:                 calldata,
+		RequestedValidatorCount:  requestedValidatorCount,
+		SufficientValidatorCount: sufficientValidatorCount,
+		Expiration:               expiration,
+		PrepareGas:               prepareGas,
+		ExecuteGas:               executeGas,
+		Interval:                 interval,
+		EndHeight:                endHeight,
+		Deposit:                  deposit,
+		Sender:                   sender,
+	}
+}
+
+// Route implements the sdk.Msg interface for MsgSubscribe.
+func (msg MsgSubscribe) Route() string { return RouterKey }
+
+// Type implements the sdk.Msg interface for MsgSubscribe.
+func (msg MsgSubscribe) Type() string { return "subscribe" }
+
+// ValidateBasic implements the sdk.Msg interface for MsgSubscribe.
+func (msg MsgSubscribe) ValidateBasic() error {
+	if msg.Sender.Empty() {
+		return sdkerrors.Wrapf(
+			ErrInvalidBasicMsg,
+			"MsgSubscribe: Sender address must not be empty.",
+		)
+	}
+	if msg.OracleScriptID <= 0 {
+		return sdkerrors.Wrapf(
+			ErrInvalidBasicMsg,
+			"MsgSubscribe: Oracle script id (%d) must be positive.",
+			msg.OracleScriptID,
+		)
+	}
+	if msg.SufficientValidatorCount <= 0 {
+		return sdkerrors.Wrapf(
+			ErrInvalidBasicMsg,
+			"MsgSubscribe: Sufficient validator count (%d) must be positive.",
+			msg.SufficientValidatorCount,
+		)
+	}
+	if msg.RequestedValidatorCount < msg.SufficientValidatorCount {
+		return sdkerrors.Wrapf(
+			ErrInvalidBasicMsg,
+			"MsgSubscribe: Request validator count (%d) must not be less than sufficient validator count (%d).",
+			msg.RequestedValidatorCount,
+			msg.SufficientValidatorCount,
+		)
+	}
+	if msg.Expiration <= 0 {
+		return sdkerrors.Wrapf(
+			ErrInvalidBasicMsg,
+			"MsgSubscribe: Expiration period (%d) must be positive.",
+			msg.Expiration,
+		)
+	}
+	if msg.PrepareGas <= 0 {
+		return sdkerrors.Wrapf(
+			ErrInvalidBasicMsg,
+			"MsgSubscribe: Prepare gas (%d) must be positive.",
+			msg.PrepareGas,
+		)
+	}
+	if msg.ExecuteGas <= 0 {
+		return sdkerrors.Wrapf(
+			ErrInvalidBasicMsg,
+			"MsgSubscribe: Execute gas (%d) must be positive.",
+			msg.ExecuteGas,
+		)
+	}
+	if msg.Interval <= 0 {
+		return sdkerrors.Wrapf(
+			ErrInvalidBasicMsg,
+			"MsgSubscribe: Interval (%d) must be positive.",
+			msg.Interval,
+		)
+	}
+	if msg.EndHeight <= 0 {
+		return sdkerrors.Wrapf(
+			ErrInvalidBasicMsg,
+			"MsgSubscribe: End height (%d) must be positive.",
+			msg.EndHeight,
+		)
+	}
+	if !msg.Deposit.IsValid() {
+		return sdkerrors.Wrapf(
+			ErrInvalidBasicMsg,
+			"MsgSubscribe: Deposit (%s) is invalid.",
+			msg.Deposit.String(),
+		)
+	}
+	if msg.Deposit.IsZero() {
+		return sdkerrors.Wrapf(
+			ErrInvalidBasicMsg,
+			"MsgSubscribe: Deposit must not be empty.",
+		)
+	}
+	return nil
+}
+
+// GetSigners implements the sdk.Msg interface for MsgSubscribe.
+func (msg MsgSubscribe) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// GetSignBytes implements the sdk.Msg interface for MsgSubscribe.
+func (msg MsgSubscribe) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// MsgCancelRequest is a message for cancelling an open request by its requester.
+type MsgCancelRequest struct {
+	RequestID RequestID      `json:"requestID"`
+	Sender    sdk.AccAddress `json:"sender"`
+}
+
+// NewMsgCancelRequest creates a new MsgCancelRequest instance.
+func NewMsgCancelRequest(
+	requestID RequestID,
+	sender sdk.AccAddress,
+) MsgCancelRequest {
+	return MsgCancelRequest{
+		RequestID: requestID,
+		Sender:    sender,
+	}
+}
+
+// Route implements the sdk.Msg interface for MsgCancelRequest.
+func (msg MsgCancelRequest) Route() string { return RouterKey }
+
+// Type implements the sdk.Msg interface for MsgCancelRequest.
+func (msg MsgCancelRequest) Type() string { return "cancel_request" }
+
+// ValidateBasic implements the sdk.Msg interface for MsgCancelRequest.
+func (msg MsgCancelRequest) ValidateBasic() error {
+	if msg.RequestID <= 0 {
+		return sdkerrors.Wrapf(ErrInvalidBasicMsg, "MsgCancelRequest: Request id (%d) must be positive.", msg.RequestID)
+	}
+	if msg.Sender.Empty() {
+		return sdkerrors.Wrapf(ErrInvalidBasicMsg, "MsgCancelRequest: Sender address must not be empty.")
+	}
+	return nil
+}
+
+// GetSigners implements the sdk.Msg interface for MsgCancelRequest.
+func (msg MsgCancelRequest) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// GetSignBytes implements the sdk.Msg interface for MsgCancelRequest.
+func (msg MsgCancelRequest) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
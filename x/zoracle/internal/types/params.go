@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/cosmos/cosmos-sdk/x/params"
+
+	"github.com/cosmos/gaia/owasm"
 )
 
 // Default parameter namespace.
@@ -48,8 +50,88 @@ const (
 
 	// Gas cost per validator for each raw data request.
 	DefaultGasPerRawDataRequestPerValidator = uint64(25000)
+
+	// The maximum number of memory pages an Owasm script can grow its memory to.
+	// Default value is 1024.
+	DefaultMaxMemoryPages = int64(1024)
+
+	// The maximum number of entries in the table of an Owasm script.
+	// Default value is 1024.
+	DefaultMaxTableSize = int64(1024)
+
+	// The maximum number of value slots available to a single Owasm function call.
+	// Default value is 65536.
+	DefaultMaxValueSlots = int64(65536)
+
+	// The maximum depth of the call stack of an Owasm script.
+	// Default value is 128.
+	DefaultMaxCallStackDepth = int64(128)
+
+	// The number of memory pages allocated to an Owasm script that does not declare its memory.
+	// Default value is 64.
+	DefaultDefaultMemoryPages = int64(64)
+
+	// The table size allocated to an Owasm script that does not declare its table.
+	// Default value is 65536.
+	DefaultDefaultTableSize = int64(65536)
+
+	// The number of blocks a request can wait in the pending list before it is served ahead of newer ones.
+	// Default value is 10.
+	DefaultPendingAgingBlocks = int64(10)
+
+	// The maximum number of blocks a request can wait in the pending list before it fails.
+	// Default value is 100.
+	DefaultMaxPendingWaitBlocks = int64(100)
+
+	// The number of blocks the data of a resolved request is kept, or 0 to keep it forever.
+	// Default value is 0.
+	DefaultResultRetentionBlocks = int64(0)
+
+	// The number of latest requests whose data is kept, or 0 to keep every request.
+	// Default value is 0.
+	DefaultResultRetentionRequests = int64(0)
+
+	// The maximum store gas that EndBlock may spend pruning the data of old requests.
+	// Default value is 1000000.
+	DefaultPruneGasLimit = uint64(1000000)
+
+	// What happens to the raw reports of a request once it is resolved: keep, delete or commit.
+	// Default value is "keep".
+	DefaultReportCleanupMode = ReportCleanupKeep
+
+	// The amount of the bond denomination charged to the balance of a subscription per unit of gas
+	// used to make each of its requests. Default value is 1.
+	DefaultSubscriptionGasPrice = uint64(1)
+
+	// The maximum number of subscriptions requested in a block. Subscriptions over the limit are
+	// requested in the following blocks. Default value is 100.
+	DefaultMaxSubscriptionsPerBlock = int64(100)
+
+	// The maximum number of blocks between the first and the end height of a subscription.
+	// Default value is 1000000.
+	DefaultMaxSubscriptionDuration = int64(1000000)
+)
+
+// Report cleanup modes.
+const (
+	// ReportCleanupKeep keeps the raw reports of resolved requests.
+	ReportCleanupKeep = "keep"
+	// ReportCleanupDelete deletes the raw reports of resolved requests.
+	ReportCleanupDelete = "delete"
+	// ReportCleanupCommit replaces the raw reports of resolved requests with a ReportCommitment.
+	ReportCleanupCommit = "commit"
 )
 
+// ValidateReportCleanupMode returns an error if the mode is not one of the report cleanup modes.
+func ValidateReportCleanupMode(mode string) error {
+	switch mode {
+	case ReportCleanupKeep, ReportCleanupDelete, ReportCleanupCommit:
+		return nil
+	default:
+		return fmt.Errorf("invalid report cleanup mode: %s", mode)
+	}
+}
+
 // Parameter store keys.
 var (
 	KeyMaxDataSourceExecutableSize      = []byte("MaxDataSourceExecutableSize")
@@ -62,20 +144,54 @@ var (
 	KeyMaxNameLength                    = []byte("MaxNameLength")
 	KeyMaxDescriptionLength             = []byte("MaxDescriptionLength")
 	KeyGasPerRawDataRequestPerValidator = []byte("GasPerRawDataRequestPerValidator")
+	KeyMaxMemoryPages                   = []byte("MaxMemoryPages")
+	KeyMaxTableSize                     = []byte("MaxTableSize")
+	KeyMaxValueSlots                    = []byte("MaxValueSlots")
+	KeyMaxCallStackDepth                = []byte("MaxCallStackDepth")
+	KeyDefaultMemoryPages               = []byte("DefaultMemoryPages")
+	KeyDefaultTableSize                 = []byte("DefaultTableSize")
+	KeyGasSchedule                      = []byte("GasSchedule")
+	KeyPendingAgingBlocks               = []byte("PendingAgingBlocks")
+	KeyMaxPendingWaitBlocks             = []byte("MaxPendingWaitBlocks")
+	KeyResultRetentionBlocks            = []byte("ResultRetentionBlocks")
+	KeyResultRetentionRequests          = []byte("ResultRetentionRequests")
+	KeyPruneGasLimit                    = []byte("PruneGasLimit")
+	KeyReportCleanupMode                = []byte("ReportCleanupMode")
+	KeyReferenceSymbols                 = []byte("ReferenceSymbols")
+	KeySubscriptionGasPrice             = []byte("SubscriptionGasPrice")
+	KeyMaxSubscriptionsPerBlock         = []byte("MaxSubscriptionsPerBlock")
+	KeyMaxSubscriptionDuration          = []byte("MaxSubscriptionDuration")
 )
 
 // Params - used for initializing default parameter for zoracle at genesis.
 type Params struct {
-	MaxDataSourceExecutableSize      int64  `json:"max_data_source_executable_size" yaml:"max_data_source_executable_size"`
-	MaxOracleScriptCodeSize          int64  `json:"max_oracle_script_code_size" yaml:"max_oracle_script_code_size"`
-	MaxCalldataSize                  int64  `json:"max_calldata_size" yaml:"max_calldata_size"`
-	MaxDataSourceCountPerRequest     int64  `json:"max_data_source_count_per_request" yaml:"max_data_source_count_per_request"`
-	MaxRawDataReportSize             int64  `json:"max_raw_data_report_size" yaml:"max_raw_data_report_size"`
-	MaxResultSize                    int64  `json:"max_result_size" yaml:"max_result_size"`
-	EndBlockExecuteGasLimit          uint64 `json:"end_block_execute_gas_limit" yaml:"end_block_execute_gas_limit"`
-	MaxNameLength                    int64  `json:"max_name_length" yaml:"max_name_length"`
-	MaxDescriptionLength             int64  `json:"max_description_length" yaml:"max_description_length"`
-	GasPerRawDataRequestPerValidator uint64 `json:"gas_per_raw_data_request" yaml:"gas_per_raw_data_request"`
+	MaxDataSourceExecutableSize      int64             `json:"max_data_source_executable_size" yaml:"max_data_source_executable_size"`
+	MaxOracleScriptCodeSize          int64             `json:"max_oracle_script_code_size" yaml:"max_oracle_script_code_size"`
+	MaxCalldataSize                  int64             `json:"max_calldata_size" yaml:"max_calldata_size"`
+	MaxDataSourceCountPerRequest     int64             `json:"max_data_source_count_per_request" yaml:"max_data_source_count_per_request"`
+	MaxRawDataReportSize             int64             `json:"max_raw_data_report_size" yaml:"max_raw_data_report_size"`
+	MaxResultSize                    int64             `json:"max_result_size" yaml:"max_result_size"`
+	EndBlockExecuteGasLimit          uint64            `json:"end_block_execute_gas_limit" yaml:"end_block_execute_gas_limit"`
+	MaxNameLength                    int64             `json:"max_name_length" yaml:"max_name_length"`
+	MaxDescriptionLength             int64             `json:"max_description_length" yaml:"max_description_length"`
+	GasPerRawDataRequestPerValidator uint64            `json:"gas_per_raw_data_request" yaml:"gas_per_raw_data_request"`
+	MaxMemoryPages                   int64             `json:"max_memory_pages" yaml:"max_memory_pages"`
+	MaxTableSize                     int64             `json:"max_table_size" yaml:"max_table_size"`
+	MaxValueSlots                    int64             `json:"max_value_slots" yaml:"max_value_slots"`
+	MaxCallStackDepth                int64             `json:"max_call_stack_depth" yaml:"max_call_stack_depth"`
+	DefaultMemoryPages               int64             `json:"default_memory_pages" yaml:"default_memory_pages"`
+	DefaultTableSize                 int64             `json:"default_table_size" yaml:"default_table_size"`
+	GasSchedule                      owasm.GasSchedule `json:"gas_schedule" yaml:"gas_schedule"`
+	PendingAgingBlocks               int64             `json:"pending_aging_blocks" yaml:"pending_aging_blocks"`
+	MaxPendingWaitBlocks             int64             `json:"max_pending_wait_blocks" yaml:"max_pending_wait_blocks"`
+	ResultRetentionBlocks            int64             `json:"result_retention_blocks" yaml:"result_retention_blocks"`
+	ResultRetentionRequests          int64             `json:"result_retention_requests" yaml:"result_retention_requests"`
+	PruneGasLimit                    uint64            `json:"prune_gas_limit" yaml:"prune_gas_limit"`
+	ReportCleanupMode                string            `json:"report_cleanup_mode" yaml:"report_cleanup_mode"`
+	ReferenceSymbols                 ReferenceSymbols  `json:"reference_symbols" yaml:"reference_symbols"`
+	SubscriptionGasPrice             uint64            `json:"subscription_gas_price" yaml:"subscription_gas_price"`
+	MaxSubscriptionsPerBlock         int64             `json:"max_subscriptions_per_block" yaml:"max_subscriptions_per_block"`
+	MaxSubscriptionDuration          int64             `json:"max_subscription_duration" yaml:"max_subscription_duration"`
 }
 
 // NewParams creates a new Params object.
@@ -90,6 +206,23 @@ func NewParams(
 	maxNameLength int64,
 	maxDescriptionLength int64,
 	gasPerRawDataRequestPerValidator uint64,
+	maxMemoryPages int64,
+	maxTableSize int64,
+	maxValueSlots int64,
+	maxCallStackDepth int64,
+	defaultMemoryPages int64,
+	defaultTableSize int64,
+	gasSchedule owasm.GasSchedule,
+	pendingAgingBlocks int64,
+	maxPendingWaitBlocks int64,
+	resultRetentionBlocks int64,
+	resultRetentionRequests int64,
+	pruneGasLimit uint64,
+	reportCleanupMode string,
+	referenceSymbols ReferenceSymbols,
+	subscriptionGasPrice uint64,
+	maxSubscriptionsPerBlock int64,
+	maxSubscriptionDuration int64,
 ) Params {
 	return Params{
 		MaxDataSourceExecutableSize:      maxDataSourceExecutableSize,
@@ -102,6 +235,23 @@ func NewParams(
 		MaxNameLength:                    maxNameLength,
 		MaxDescriptionLength:             maxDescriptionLength,
 		GasPerRawDataRequestPerValidator: gasPerRawDataRequestPerValidator,
+		MaxMemoryPages:                   maxMemoryPages,
+		MaxTableSize:                     maxTableSize,
+		MaxValueSlots:                    maxValueSlots,
+		MaxCallStackDepth:                maxCallStackDepth,
+		DefaultMemoryPages:               defaultMemoryPages,
+		DefaultTableSize:                 defaultTableSize,
+		GasSchedule:                      gasSchedule,
+		PendingAgingBlocks:               pendingAgingBlocks,
+		MaxPendingWaitBlocks:             maxPendingWaitBlocks,
+		ResultRetentionBlocks:            resultRetentionBlocks,
+		ResultRetentionRequests:          resultRetentionRequests,
+		PruneGasLimit:                    pruneGasLimit,
+		ReportCleanupMode:                reportCleanupMode,
+		ReferenceSymbols:                 referenceSymbols,
+		SubscriptionGasPrice:             subscriptionGasPrice,
+		MaxSubscriptionsPerBlock:         maxSubscriptionsPerBlock,
+		MaxSubscriptionDuration:          maxSubscriptionDuration,
 	}
 }
 
@@ -118,6 +268,26 @@ func (p Params) String() string {
   MaxNameLength:                    %d
   MaxDescriptionLength:             %d
   GasPerRawDataRequestPerValidator: %d
+  MaxMemoryPages:                   %d
+  MaxTableSize:                     %d
+  MaxValueSlots:                    %d
+  MaxCallStackDepth:                %d
+  DefaultMemoryPages:               %d
+  DefaultTableSize:                 %d
+  GasScheduleHash:                  %X
+  GasScheduleOpCount:               %d
+  GasScheduleHostFunctionCount:     %d
+  GasScheduleUnknownOpCost:         %d
+  PendingAgingBlocks:               %d
+  MaxPendingWaitBlocks:             %d
+  ResultRetentionBlocks:            %d
+  ResultRetentionRequests:          %d
+  PruneGasLimit:                    %d
+  ReportCleanupMode:                %s
+  ReferenceSymbolCount:             %d
+  SubscriptionGasPrice:             %d
+  MaxSubscriptionsPerBlock:         %d
+  MaxSubscriptionDuration:          %d
 `, p.MaxDataSourceExecutableSize,
 		p.MaxOracleScriptCodeSize,
 		p.MaxCalldataSize,
@@ -128,9 +298,41 @@ func (p Params) String() string {
 		p.MaxNameLength,
 		p.MaxDescriptionLength,
 		p.GasPerRawDataRequestPerValidator,
+		p.MaxMemoryPages,
+		p.MaxTableSize,
+		p.MaxValueSlots,
+		p.MaxCallStackDepth,
+		p.DefaultMemoryPages,
+		p.DefaultTableSize,
+		p.GasSchedule.Hash(),
+		len(p.GasSchedule.Costs),
+		len(p.GasSchedule.HostCosts),
+		p.GasSchedule.UnknownCost,
+		p.PendingAgingBlocks,
+		p.MaxPendingWaitBlocks,
+		p.ResultRetentionBlocks,
+		p.ResultRetentionRequests,
+		p.PruneGasLimit,
+		p.ReportCleanupMode,
+		len(p.ReferenceSymbols),
+		p.SubscriptionGasPrice,
+		p.MaxSubscriptionsPerBlock,
+		p.MaxSubscriptionDuration,
 	)
 }
 
+// VMLimits returns the Owasm virtual machine resource limits set by the parameters.
+func (p Params) VMLimits() owasm.Limits {
+	return owasm.Limits{
+		MaxMemoryPages:     p.MaxMemoryPages,
+		MaxTableSize:       p.MaxTableSize,
+		MaxValueSlots:      p.MaxValueSlots,
+		MaxCallStackDepth:  p.MaxCallStackDepth,
+		DefaultMemoryPages: p.DefaultMemoryPages,
+		DefaultTableSize:   p.DefaultTableSize,
+	}
+}
+
 // ParamSetPairs implements the params.ParamSet interface for Params.
 func (p *Params) ParamSetPairs() params.ParamSetPairs {
 	return params.ParamSetPairs{
@@ -144,6 +346,23 @@ func (p *Params) ParamSetPairs() params.ParamSetPairs {
 		{Key: KeyMaxNameLength, Value: &p.MaxNameLength},
 		{Key: KeyMaxDescriptionLength, Value: &p.MaxDescriptionLength},
 		{Key: KeyGasPerRawDataRequestPerValidator, Value: &p.GasPerRawDataRequestPerValidator},
+		{Key: KeyMaxMemoryPages, Value: &p.MaxMemoryPages},
+		{Key: KeyMaxTableSize, Value: &p.MaxTableSize},
+		{Key: KeyMaxValueSlots, Value: &p.MaxValueSlots},
+		{Key: KeyMaxCallStackDepth, Value: &p.MaxCallStackDepth},
+		{Key: KeyDefaultMemoryPages, Value: &p.DefaultMemoryPages},
+		{Key: KeyDefaultTableSize, Value: &p.DefaultTableSize},
+		{Key: KeyGasSchedule, Value: &p.GasSchedule},
+		{Key: KeyPendingAgingBlocks, Value: &p.PendingAgingBlocks},
+		{Key: KeyMaxPendingWaitBlocks, Value: &p.MaxPendingWaitBlocks},
+		{Key: KeyResultRetentionBlocks, Value: &p.ResultRetentionBlocks},
+		{Key: KeyResultRetentionRequests, Value: &p.ResultRetentionRequests},
+		{Key: KeyPruneGasLimit, Value: &p.PruneGasLimit},
+		{Key: KeyReportCleanupMode, Value: &p.ReportCleanupMode},
+		{Key: KeyReferenceSymbols, Value: &p.ReferenceSymbols},
+		{Key: KeySubscriptionGasPrice, Value: &p.SubscriptionGasPrice},
+		{Key: KeyMaxSubscriptionsPerBlock, Value: &p.MaxSubscriptionsPerBlock},
+		{Key: KeyMaxSubscriptionDuration, Value: &p.MaxSubscriptionDuration},
 	}
 }
 
@@ -160,5 +379,22 @@ func DefaultParams() Params {
 		DefaultMaxNameLength,
 		DefaultMaxDescriptionLength,
 		DefaultGasPerRawDataRequestPerValidator,
+		DefaultMaxMemoryPages,
+		DefaultMaxTableSize,
+		DefaultMaxValueSlots,
+		DefaultMaxCallStackDepth,
+		DefaultDefaultMemoryPages,
+		DefaultDefaultTableSize,
+		owasm.DefaultGasSchedule(),
+		DefaultPendingAgingBlocks,
+		DefaultMaxPendingWaitBlocks,
+		DefaultResultRetentionBlocks,
+		DefaultResultRetentionRequests,
+		DefaultPruneGasLimit,
+		DefaultReportCleanupMode,
+		ReferenceSymbols{},
+		DefaultSubscriptionGasPrice,
+		DefaultMaxSubscriptionsPerBlock,
+		DefaultMaxSubscriptionDuration,
 	)
 }
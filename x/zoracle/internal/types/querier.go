@@ -8,14 +8,21 @@ import (
 
 // query endpoints
 const (
-	QueryDataSourceByID   = "data_source"
-	QueryDataSources      = "data_sources"
-	QueryOracleScriptByID = "oracle_script"
-	QueryOracleScripts    = "oracle_scripts"
-	QueryRequestByID      = "request"
-	QueryRequests         = "requests"
-	QueryPending          = "pending_request"
-	QueryRequestNumber    = "request_number"
+	QueryDataSourceByID      = "data_source"
+	QueryDataSources         = "data_sources"
+	QueryOracleScriptByID    = "oracle_script"
+	QueryOracleScripts       = "oracle_scripts"
+	QueryRequestByID         = "request"
+	QueryRequests            = "requests"
+	QueryPending             = "pending_request"
+	QueryRequestNumber       = "request_number"
+	QueryParams              = "params"
+	QueryLatestResult        = "latest_result"
+	QueryReferenceData       = "reference_data"
+	QuerySubscriptionByID    = "subscription"
+	QueryRequestsByRequester = "requests_by_requester"
+	QuerySearchRequests      = "search_requests"
+	QueryPendingAssignments  = "pending_assignments"
 )
 
 type RawBytes []byte
@@ -88,6 +95,8 @@ type RequestQuerierInfo struct {
 	RawDataRequests []RawDataRequestWithExternalID `json:"rawDataRequests"`
 	Reports         []ReportWithValidator          `json:"reports"`
 	Result          Result                         `json:"result"`
+	// ReportCommitment replaces Reports once the reports are cleaned up in commit mode.
+	ReportCommitment *ReportCommitment `json:"reportCommitment,omitempty"`
 }
 
 func NewRequestQuerierInfo(
@@ -96,12 +105,138 @@ func NewRequestQuerierInfo(
 	rawDataRequests []RawDataRequestWithExternalID,
 	reports []ReportWithValidator,
 	result Result,
+	reportCommitment *ReportCommitment,
 ) RequestQuerierInfo {
 	return RequestQuerierInfo{
-		ID:              id,
-		Request:         request,
-		RawDataRequests: rawDataRequests,
-		Reports:         reports,
-		Result:          result,
+		ID:               id,
+		Request:          request,
+		RawDataRequests:  rawDataRequests,
+		Reports:          reports,
+		Result:           result,
+		ReportCommitment: reportCommitment,
+	}
+}
+
+// LatestResultQuerierInfo is the latest result of an oracle script and calldata, with the ID of
+// the request that produced it.
+type LatestResultQuerierInfo struct {
+	RequestID RequestID `json:"requestID"`
+	Result    Result    `json:"result"`
+}
+
+func NewLatestResultQuerierInfo(requestID RequestID, result Result) LatestResultQuerierInfo {
+	return LatestResultQuerierInfo{
+		RequestID: requestID,
+		Result:    result,
+	}
+}
+
+// QueryRequestsParams defines the filters and the cursor of a request search. Filters left at
+// their zero value match every request.
+type QueryRequestsParams struct {
+	StartID        RequestID      `json:"start_id"`
+	Limit          int            `json:"limit"`
+	OracleScriptID OracleScriptID `json:"oracle_script_id"`
+	Requester      sdk.AccAddress `json:"requester"`
+	ResolveStatus  string         `json:"resolve_status"`
+	Validator      sdk.ValAddress `json:"validator"`
+	MinHeight      int64          `json:"min_height"`
+	MaxHeight      int64          `json:"max_height"`
+	SourcePort     string         `json:"source_port"`
+	SourceChannel  string         `json:"source_channel"`
+}
+
+func NewQueryRequestsParams(
+	startID RequestID,
+	limit int,
+	oracleScriptID OracleScriptID,
+	requester sdk.AccAddress,
+	resolveStatus string,
+	validator sdk.ValAddress,
+	minHeight int64,
+	maxHeight int64,
+	sourcePort string,
+	sourceChannel string,
+) QueryRequestsParams {
+	return QueryRequestsParams{
+		StartID:        startID,
+		Limit:          limit,
+		OracleScriptID: oracleScriptID,
+		Requester:      requester,
+		ResolveStatus:  resolveStatus,
+		Validator:      validator,
+		MinHeight:      minHeight,
+		MaxHeight:      maxHeight,
+		SourcePort:     sourcePort,
+		SourceChannel:  sourceChannel,
+	}
+}
+
+// Validate returns an error if the search parameters are malformed.
+func (params QueryRequestsParams) Validate() error {
+	if params.Limit < 1 || params.Limit > 100 {
+		return fmt.Errorf("limit should be >= 1 and <= 100")
+	}
+	if params.ResolveStatus != "" {
+		if _, err := ResolveStatusFromString(params.ResolveStatus); err != nil {
+			return err
+		}
+	}
+	if params.MinHeight < 0 || params.MaxHeight < 0 {
+		return fmt.Errorf("heights must not be negative")
+	}
+	if params.MaxHeight != 0 && params.MinHeight > params.MaxHeight {
+		return fmt.Errorf("min height (%d) exceeds max height (%d)", params.MinHeight, params.MaxHeight)
+	}
+	if (params.SourcePort == "") != (params.SourceChannel == "") {
+		return fmt.Errorf("source port and source channel must be given together")
+	}
+	return nil
+}
+
+// RequestSearchResult is a page of a request search. NextID is the start ID of the next page, or
+// zero if there are no more requests to search. A page may hold fewer requests than the limit, or
+// none at all, while NextID is non-zero, since each page checks a bounded number of requests.
+type RequestSearchResult struct {
+	Requests []RequestQuerierInfo `json:"requests"`
+	NextID   RequestID            `json:"next_id"`
+}
+
+func NewRequestSearchResult(requests []RequestQuerierInfo, nextID RequestID) RequestSearchResult {
+	return RequestSearchResult{
+		Requests: requests,
+		NextID:   nextID,
+	}
+}
+
+// PendingAssignmentQuerierInfo is an open request that a validator has yet to report to, along
+// with the raw data requests it has to report on and the data sources it needs to run.
+type PendingAssignmentQuerierInfo struct {
+	RequestID        RequestID                      `json:"requestID"`
+	OracleScriptID   OracleScriptID                 `json:"oracleScriptID"`
+	Calldata         []byte                         `json:"calldata"`
+	RequestHeight    int64                          `json:"requestHeight"`
+	ExpirationHeight int64                          `json:"expirationHeight"`
+	RawDataRequests  []RawDataRequestWithExternalID `json:"rawDataRequests"`
+	DataSourceIDs    []DataSourceID                 `json:"dataSourceIDs"`
+}
+
+func NewPendingAssignmentQuerierInfo(
+	requestID RequestID,
+	oracleScriptID OracleScriptID,
+	calldata []byte,
+	requestHeight int64,
+	expirationHeight int64,
+	rawDataRequests []RawDataRequestWithExternalID,
+	dataSourceIDs []DataSourceID,
+) PendingAssignmentQuerierInfo {
+	return PendingAssignmentQuerierInfo{
+		RequestID:        requestID,
+		OracleScriptID:   oracleScriptID,
+		Calldata:         calldata,
+		RequestHeight:    requestHeight,
+		ExpirationHeight: expirationHeight,
+		RawDataRequests:  rawDataRequests,
+		DataSourceIDs:    dataSourceIDs,
 	}
 }
@@ -0,0 +1,146 @@
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// ReferenceDataAccountName is the name of the module account that pays for the requests
+// made on behalf of reference symbols.
+const ReferenceDataAccountName = "refdata"
+
+// ReferenceSymbol is a currency pair whose rate is refreshed by requesting an oracle script on
+// schedule. The result of the oracle script must be an 8-byte big-endian rate scaled by
+// Multiplier.
+type ReferenceSymbol struct {
+	Symbol                   string         `json:"symbol" yaml:"symbol"`
+	OracleScriptID           OracleScriptID `json:"oracle_script_id" yaml:"oracle_script_id"`
+	Calldata                 []byte         `json:"calldata" yaml:"calldata"`
+	RequestedValidatorCount  int64          `json:"requested_validator_count" yaml:"requested_validator_count"`
+	SufficientValidatorCount int64          `json:"sufficient_validator_count" yaml:"sufficient_validator_count"`
+	Expiration               int64          `json:"expiration" yaml:"expiration"`
+	PrepareGas               uint64         `json:"prepare_gas" yaml:"prepare_gas"`
+	ExecuteGas               uint64         `json:"execute_gas" yaml:"execute_gas"`
+	RefreshInterval          int64          `json:"refresh_interval" yaml:"refresh_interval"`
+	Multiplier               uint64         `json:"multiplier" yaml:"multiplier"`
+}
+
+// NewReferenceSymbol creates a new ReferenceSymbol instance.
+func NewReferenceSymbol(
+	symbol string,
+	oracleScriptID OracleScriptID,
+	calldata []byte,
+	requestedValidatorCount int64,
+	sufficientValidatorCount int64,
+	expiration int64,
+	prepareGas uint64,
+	executeGas uint64,
+	refreshInterval int64,
+	multiplier uint64,
+) ReferenceSymbol {
+	return ReferenceSymbol{
+		Symbol:                   symbol,
+		OracleScriptID:           oracleScriptID,
+		Calldata:                 calldata,
+		RequestedValidatorCount:  requestedValidatorCount,
+		SufficientValidatorCount: sufficientValidatorCount,
+		Expiration:               expiration,
+		PrepareGas:               prepareGas,
+		ExecuteGas:               executeGas,
+		RefreshInterval:          refreshInterval,
+		Multiplier:               multiplier,
+	}
+}
+
+// ReferenceSymbolName returns the symbol of the pair of the given base and quote currencies.
+func ReferenceSymbolName(base, quote string) string {
+	return base + "/" + quote
+}
+
+// Validate returns an error if the reference symbol could never produce a request.
+func (s ReferenceSymbol) Validate() error {
+	pair := strings.Split(s.Symbol, "/")
+	if len(pair) != 2 || pair[0] == "" || pair[1] == "" {
+		return fmt.Errorf("invalid reference symbol %q, must be BASE/QUOTE", s.Symbol)
+	}
+	if s.OracleScriptID <= 0 {
+		return fmt.Errorf("reference symbol %s: oracle script ID must be positive", s.Symbol)
+	}
+	if s.SufficientValidatorCount <= 0 || s.SufficientValidatorCount > s.RequestedValidatorCount {
+		return fmt.Errorf(
+			"reference symbol %s: sufficient validator count (%d) must be between 1 and requested validator count (%d)",
+			s.Symbol, s.SufficientValidatorCount, s.RequestedValidatorCount,
+		)
+	}
+	if s.Expiration <= 0 {
+		return fmt.Errorf("reference symbol %s: expiration must be positive", s.Symbol)
+	}
+	if s.PrepareGas == 0 || s.ExecuteGas == 0 {
+		return fmt.Errorf("reference symbol %s: prepare gas and execute gas must be positive", s.Symbol)
+	}
+	if s.RefreshInterval <= 0 {
+		return fmt.Errorf("reference symbol %s: refresh interval must be positive", s.Symbol)
+	}
+	if s.Multiplier == 0 {
+		return fmt.Errorf("reference symbol %s: multiplier must be positive", s.Symbol)
+	}
+	return nil
+}
+
+// ReferenceSymbols is the list of reference symbols registered by governance.
+type ReferenceSymbols []ReferenceSymbol
+
+// Validate returns an error if any symbol is invalid or registered twice.
+func (symbols ReferenceSymbols) Validate() error {
+	seen := make(map[string]bool)
+	for _, symbol := range symbols {
+		if err := symbol.Validate(); err != nil {
+			return err
+		}
+		if seen[symbol.Symbol] {
+			return fmt.Errorf("duplicate reference symbol %s", symbol.Symbol)
+		}
+		seen[symbol.Symbol] = true
+	}
+	return nil
+}
+
+// Find returns the reference symbol with the given name.
+func (symbols ReferenceSymbols) Find(name string) (ReferenceSymbol, bool) {
+	for _, symbol := range symbols {
+		if symbol.Symbol == name {
+			return symbol, true
+		}
+	}
+	return ReferenceSymbol{}, false
+}
+
+// ReferenceData is the rate of a currency pair, along with the time at which it was aggregated
+// and the request that produced it.
+type ReferenceData struct {
+	Rate        sdk.Dec   `json:"rate"`
+	LastUpdated int64     `json:"lastUpdated"`
+	RequestID   RequestID `json:"requestID"`
+}
+
+// NewReferenceData creates a new ReferenceData instance.
+func NewReferenceData(rate sdk.Dec, lastUpdated int64, requestID RequestID) ReferenceData {
+	return ReferenceData{
+		Rate:        rate,
+		LastUpdated: lastUpdated,
+		RequestID:   requestID,
+	}
+}
+
+// DecodeReferenceRate decodes the result data of a reference symbol request into a rate.
+func DecodeReferenceRate(data []byte, multiplier uint64) (sdk.Dec, error) {
+	if len(data) != 8 {
+		return sdk.Dec{}, fmt.Errorf("expect reference data of 8 bytes but got %d bytes", len(data))
+	}
+	rate := new(big.Int).SetUint64(binary.BigEndian.Uint64(data))
+	return sdk.NewDecFromBigInt(rate).QuoInt(sdk.NewIntFromUint64(multiplier)), nil
+}
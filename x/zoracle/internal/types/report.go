@@ -1,7 +1,10 @@
 package types
 
 import (
+	"crypto/sha256"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/crypto/merkle"
 )
 
 type RawDataReport struct {
@@ -49,3 +52,40 @@ func NewReportWithValidator(
 		Validator:      valAddress,
 	}
 }
+
+// ReportHash is the hash of a raw data report that has been replaced by a report commitment.
+type ReportHash struct {
+	Validator  sdk.ValAddress `json:"validator"`
+	ExternalID ExternalID     `json:"externalID"`
+	Hash       []byte         `json:"hash"`
+}
+
+// ReportCommitment replaces the raw data reports of a resolved request. Root is the simple Merkle
+// root of the report hashes, which are ordered by external ID and then by validator address.
+type ReportCommitment struct {
+	Root         []byte       `json:"root"`
+	ReportHashes []ReportHash `json:"reportHashes"`
+}
+
+// NewReportCommitment creates a new ReportCommitment instance from the given report hashes.
+func NewReportCommitment(reportHashes []ReportHash) ReportCommitment {
+	leaves := make([][]byte, len(reportHashes))
+	for i, reportHash := range reportHashes {
+		leaves[i] = reportHash.Hash
+	}
+	return ReportCommitment{
+		Root:         merkle.SimpleHashFromByteSlices(leaves),
+		ReportHashes: reportHashes,
+	}
+}
+
+// HashRawDataReport returns the SHA-256 hash of the external ID, exit code, length-prefixed
+// validator address and data of a raw data report.
+func HashRawDataReport(validator sdk.ValAddress, externalID ExternalID, report RawDataReport) []byte {
+	bz := append(int64ToBytes(int64(externalID)), report.ExitCode)
+	bz = append(bz, int64ToBytes(int64(len(validator)))...)
+	bz = append(bz, validator...)
+	bz = append(bz, report.Data...)
+	hash := sha256.Sum256(bz)
+	return hash[:]
+}
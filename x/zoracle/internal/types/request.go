@@ -1,6 +1,8 @@
 package types
 
 import (
+	"fmt"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
@@ -10,8 +12,69 @@ const (
 	Open ResolveStatus = iota
 	Success
 	Failure
+	Cancelled
+)
+
+// ResolveStatusFromString returns the resolve status with the given name, as used in queries.
+func ResolveStatusFromString(name string) (ResolveStatus, error) {
+	switch name {
+	case "open":
+		return Open, nil
+	case "success":
+		return Success, nil
+	case "failure":
+		return Failure, nil
+	case "cancelled":
+		return Cancelled, nil
+	default:
+		return 0, fmt.Errorf("unknown resolve status %q", name)
+	}
+}
+
+// FailureReason describes why a request was resolved with Failure status.
+type FailureReason int8
+
+const (
+	FailureReasonNone FailureReason = iota
+	FailureReasonExecuteGasOverLimit
+	FailureReasonBadEnvironment
+	FailureReasonOracleScriptNotFound
+	FailureReasonGasScheduleNotFound
+	FailureReasonOutOfGas
+	FailureReasonExecutionError
+	FailureReasonBadResult
+	FailureReasonPendingTimeout
+	FailureReasonExpired
 )
 
+// String returns the name of the failure reason as used in events.
+func (reason FailureReason) String() string {
+	switch reason {
+	case FailureReasonNone:
+		return "none"
+	case FailureReasonExecuteGasOverLimit:
+		return "execute_gas_over_limit"
+	case FailureReasonBadEnvironment:
+		return "bad_environment"
+	case FailureReasonOracleScriptNotFound:
+		return "oracle_script_not_found"
+	case FailureReasonGasScheduleNotFound:
+		return "gas_schedule_not_found"
+	case FailureReasonOutOfGas:
+		return "out_of_gas"
+	case FailureReasonExecutionError:
+		return "execution_error"
+	case FailureReasonBadResult:
+		return "bad_result"
+	case FailureReasonPendingTimeout:
+		return "pending_timeout"
+	case FailureReasonExpired:
+		return "expired"
+	default:
+		return "unknown"
+	}
+}
+
 // Request is a data structure that stores the detail of a request to an oracle script.
 type Request struct {
 	OracleScriptID           OracleScriptID   `json:"oracleScriptID"`
@@ -26,6 +89,14 @@ type Request struct {
 	ResolveStatus            ResolveStatus    `json:"resolveStatus"`
 	SourcePort               string           `json:"source_port" yaml:"source_port"`
 	SourceChannel            string           `json:"source_channel" yaml:"source_channel"`
+	GasScheduleVersion       uint64           `json:"gasScheduleVersion"`
+	FailureReason            FailureReason    `json:"failureReason"`
+	PendingHeight            int64            `json:"pendingHeight"`
+	PriorityFee              sdk.Coins        `json:"priorityFee"`
+	ResultHash               []byte           `json:"resultHash"`
+	Pruned                   bool             `json:"pruned"`
+	Requester                sdk.AccAddress   `json:"requester"`
+	Expired                  bool             `json:"expired"`
 }
 
 // NewRequest creates a new Request instance.
@@ -40,6 +111,9 @@ func NewRequest(
 	executeGas uint64,
 	sourcePort string,
 	sourceChannel string,
+	gasScheduleVersion uint64,
+	priorityFee sdk.Coins,
+	requester sdk.AccAddress,
 ) Request {
 	return Request{
 		OracleScriptID:           oracleScriptID,
@@ -53,7 +127,20 @@ func NewRequest(
 		ResolveStatus:            Open,
 		SourcePort:               sourcePort,
 		SourceChannel:            sourceChannel,
+		GasScheduleVersion:       gasScheduleVersion,
+		PriorityFee:              priorityFee,
+		Requester:                requester,
+	}
+}
+
+// PendingBlocks returns the number of blocks that the pending request has waited at the given
+// height. Requests that became pending before pending heights were stored have a zero pending
+// height, and count as pending from the given height.
+func (request Request) PendingBlocks(height int64) int64 {
+	if request.PendingHeight == 0 {
+		return 0
 	}
+	return height - request.PendingHeight
 }
 
 // RawDataRequest is a data structure that store what datasource and calldata will be used in request.
@@ -34,11 +34,11 @@ func NewResult(
 	}
 }
 
-// MustDecodeResult is a helper function for decoding bytes to Result. The function panics if the
+// DecodeResult is a helper function for decoding bytes to Result. It returns an error if the
 // given input is less than 40 bytes long.
-func MustDecodeResult(b []byte) Result {
+func DecodeResult(b []byte) (Result, error) {
 	if len(b) < 40 {
-		panic(fmt.Errorf("Expect size of input to be at least 40 bytes but got %d bytes", len(b)))
+		return Result{}, fmt.Errorf("Expect size of input to be at least 40 bytes but got %d bytes", len(b))
 	}
 	return Result{
 		RequestTime:              int64(binary.BigEndian.Uint64(b[0:8])),
@@ -47,7 +47,17 @@ func MustDecodeResult(b []byte) Result {
 		SufficientValidatorCount: int64(binary.BigEndian.Uint64(b[24:32])),
 		ReportedValidatorsCount:  int64(binary.BigEndian.Uint64(b[32:40])),
 		Data:                     b[40:],
+	}, nil
+}
+
+// MustDecodeResult is a helper function for decoding bytes to Result. The function panics if the
+// given input is less than 40 bytes long.
+func MustDecodeResult(b []byte) Result {
+	result, err := DecodeResult(b)
+	if err != nil {
+		panic(err)
 	}
+	return result
 }
 
 // Bytes is a helper function for encoding Result to bytes.
@@ -0,0 +1,60 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Subscription is a data structure that stores a request template that is requested every
+// Interval blocks until EndHeight, with the gas and data source fees of its requests paid out of
+// its escrowed Balance.
+type Subscription struct {
+	Owner                    sdk.AccAddress `json:"owner"`
+	OracleScriptID           OracleScriptID `json:"oracleScriptID"`
+	Calldata                 []byte         `json:"calldata"`
+	RequestedValidatorCount  int64          `json:"requestedValidatorCount"`
+	SufficientValidatorCount int64          `json:"sufficientValidatorCount"`
+	Expiration               int64          `json:"expiration"`
+	PrepareGas               uint64         `json:"prepareGas"`
+	ExecuteGas               uint64         `json:"executeGas"`
+	Interval                 int64          `json:"interval"`
+	EndHeight                int64          `json:"endHeight"`
+	Balance                  sdk.Coins      `json:"balance"`
+	NextHeight               int64          `json:"nextHeight"`
+}
+
+// NewSubscription creates a new Subscription instance.
+func NewSubscription(
+	owner sdk.AccAddress,
+	oracleScriptID OracleScriptID,
+	calldata []byte,
+	requestedValidatorCount int64,
+	sufficientValidatorCount int64,
+	expiration int64,
+	prepareGas uint64,
+	executeGas uint64,
+	interval int64,
+	endHeight int64,
+	balance sdk.Coins,
+	nextHeight int64,
+) Subscription {
+	return Subscription{
+		Owner:                    owner,
+		OracleScriptID:           oracleScriptID,
+		Calldata:                 calldata,
+		RequestedValidatorCount:  requestedValidatorCount,
+		SufficientValidatorCount: sufficientValidatorCount,
+		Expiration:               expiration,
+		PrepareGas:               prepareGas,
+		ExecuteGas:               executeGas,
+		Interval:                 interval,
+		EndHeight:                endHeight,
+		Balance:                  balance,
+		NextHeight:               nextHeight,
+	}
+}
+
+// Reasons for which a subscription is closed.
+const (
+	SubscriptionClosedEnded               = "ended"
+	SubscriptionClosedInsufficientBalance = "insufficient_balance"
+)
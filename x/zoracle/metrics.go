@@ -0,0 +1,146 @@
+package zoracle
+
+import (
+	"sync"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cosmos/gaia/owasm"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "zoracle"
+)
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Number of data requests created.
+	RequestsCreated metrics.Counter
+	// Number of raw data reports received from validators.
+	ReportsReceived metrics.Counter
+	// Number of requests resolved, labeled by status.
+	RequestsResolved metrics.Counter
+	// Owasm execute gas consumed by the last EndBlock.
+	EndBlockGasConsumed metrics.Gauge
+	// EndBlockExecuteGasLimit parameter at the last EndBlock.
+	EndBlockGasLimit metrics.Gauge
+	// Number of requests waiting to be resolved after the last EndBlock.
+	PendingRequests metrics.Gauge
+	// Histogram of Owasm execution durations in seconds, labeled by entry.
+	OwasmExecutionDuration metrics.Histogram
+}
+
+var (
+	prometheusMetricsOnce sync.Once
+	prometheusMetrics     *Metrics
+)
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue"). It also exposes the hits and misses of the compiled Owasm
+// script cache. The metrics are registered to the default Prometheus
+// registry, which Tendermint serves, only on the first call; later calls
+// return the same metrics and ignore their arguments.
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	prometheusMetricsOnce.Do(func() {
+		prometheusMetrics = newPrometheusMetrics(namespace, labelsAndValues...)
+	})
+	return prometheusMetrics
+}
+
+func newPrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	constLabels := stdprometheus.Labels{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+		constLabels[labelsAndValues[i]] = labelsAndValues[i+1]
+	}
+	stdprometheus.MustRegister(
+		stdprometheus.NewCounterFunc(stdprometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   MetricsSubsystem,
+			Name:        "owasm_cache_hits",
+			Help:        "Number of Owasm executions that found the compiled script in the cache.",
+			ConstLabels: constLabels,
+		}, func() float64 { return float64(owasm.GetCache().Hits()) }),
+		stdprometheus.NewCounterFunc(stdprometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   MetricsSubsystem,
+			Name:        "owasm_cache_misses",
+			Help:        "Number of Owasm executions that had to compile the script.",
+			ConstLabels: constLabels,
+		}, func() float64 { return float64(owasm.GetCache().Misses()) }),
+	)
+	return &Metrics{
+		RequestsCreated: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "requests_created",
+			Help:      "Number of data requests created.",
+		}, labels).With(labelsAndValues...),
+		ReportsReceived: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "reports_received",
+			Help:      "Number of raw data reports received from validators.",
+		}, labels).With(labelsAndValues...),
+		RequestsResolved: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "requests_resolved",
+			Help:      "Number of requests resolved, labeled by status.",
+		}, append(labels, "status")).With(labelsAndValues...),
+		EndBlockGasConsumed: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "end_block_gas_consumed",
+			Help:      "Owasm execute gas consumed by the last EndBlock.",
+		}, labels).With(labelsAndValues...),
+		EndBlockGasLimit: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "end_block_gas_limit",
+			Help:      "Maximum Owasm execute gas that EndBlock may consume.",
+		}, labels).With(labelsAndValues...),
+		PendingRequests: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "pending_requests",
+			Help:      "Number of requests waiting to be resolved.",
+		}, labels).With(labelsAndValues...),
+		OwasmExecutionDuration: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "owasm_execution_duration_seconds",
+			Help:      "Owasm execution durations in seconds, labeled by entry.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.0001, 4, 10),
+		}, append(labels, "entry")).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		RequestsCreated:        discard.NewCounter(),
+		ReportsReceived:        discard.NewCounter(),
+		RequestsResolved:       discard.NewCounter(),
+		EndBlockGasConsumed:    discard.NewGauge(),
+		EndBlockGasLimit:       discard.NewGauge(),
+		PendingRequests:        discard.NewGauge(),
+		OwasmExecutionDuration: discard.NewHistogram(),
+	}
+}
+
+// moduleMetrics is the process-wide metrics of the zoracle module.
+var moduleMetrics = NopMetrics()
+
+// SetMetrics replaces the process-wide metrics of the zoracle module. It must be called
+// before the application starts processing blocks.
+func SetMetrics(metrics *Metrics) {
+	moduleMetrics = metrics
+}
@@ -0,0 +1,138 @@
+package zoracle
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/go-kit/kit/metrics"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/cosmos/gaia/x/zoracle/internal/keeper"
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+func TestPrometheusMetricsRegistersOnce(t *testing.T) {
+	first := PrometheusMetrics("test", "chain_id", "test-chain")
+	// A second call, for example from a second app instance in the same process, must not
+	// panic on duplicate registration.
+	second := PrometheusMetrics("test", "chain_id", "test-chain")
+	if first != second {
+		t.Error("expected later calls to return the same metrics")
+	}
+}
+
+// testCounter is a counter that adds up every increment regardless of the labels.
+type testCounter struct {
+	value *float64
+}
+
+func (c testCounter) With(labelValues ...string) metrics.Counter { return c }
+func (c testCounter) Add(delta float64)                          { *c.value += delta }
+
+func TestCancelRequestCountedOutsideCheckTxOnly(t *testing.T) {
+	var resolved float64
+	testMetrics := NopMetrics()
+	testMetrics.RequestsResolved = testCounter{&resolved}
+	SetMetrics(testMetrics)
+	defer SetMetrics(NopMetrics())
+
+	ctx, k := keeper.CreateTestInput()
+	requester := sdk.AccAddress([]byte("requester"))
+	for id := RequestID(1); id <= 2; id++ {
+		k.SetRequest(ctx, id, types.NewRequest(
+			1, []byte("calldata"), []sdk.ValAddress{sdk.ValAddress([]byte("validator"))}, 1,
+			ctx.BlockHeight(), ctx.BlockTime().Unix(), ctx.BlockHeight()+100, 1000, "", "", 0,
+			sdk.Coins{}, requester,
+		))
+	}
+
+	if _, err := handleMsgCancelRequest(ctx.WithIsCheckTx(true), k, types.NewMsgCancelRequest(1, requester)); err != nil {
+		t.Fatal(err)
+	}
+	if value := resolved; value != 0 {
+		t.Errorf("resolved requests %v after CheckTx, expected 0", value)
+	}
+	if _, err := handleMsgCancelRequest(ctx, k, types.NewMsgCancelRequest(2, requester)); err != nil {
+		t.Fatal(err)
+	}
+	if value := resolved; value != 1 {
+		t.Errorf("resolved requests %v after DeliverTx, expected 1", value)
+	}
+}
+
+// testLabeledCounter adds up the increments of each combination of label values.
+type testLabeledCounter struct {
+	values map[string]float64
+	labels string
+}
+
+func (c testLabeledCounter) With(labelValues ...string) metrics.Counter {
+	return testLabeledCounter{c.values, c.labels + strings.Join(labelValues, "=")}
+}
+func (c testLabeledCounter) Add(delta float64) { c.values[c.labels] += delta }
+
+// testHistogram counts the observations of each combination of label values.
+type testHistogram struct {
+	counts map[string]int
+	labels string
+}
+
+func (h testHistogram) With(labelValues ...string) metrics.Histogram {
+	return testHistogram{h.counts, h.labels + strings.Join(labelValues, "=")}
+}
+func (h testHistogram) Observe(value float64) { h.counts[h.labels]++ }
+
+// testGauge keeps the last value set regardless of the labels.
+type testGauge struct {
+	value *float64
+}
+
+func (g testGauge) With(labelValues ...string) metrics.Gauge { return g }
+func (g testGauge) Set(value float64)                        { *g.value = value }
+func (g testGauge) Add(delta float64)                        { *g.value += delta }
+
+func TestEndBlockResolveMetricsAndLogs(t *testing.T) {
+	resolved := make(map[string]float64)
+	durations := make(map[string]int)
+	gasConsumed, pending := float64(0), float64(-1)
+	testMetrics := NopMetrics()
+	testMetrics.RequestsResolved = testLabeledCounter{values: resolved}
+	testMetrics.OwasmExecutionDuration = testHistogram{counts: durations}
+	testMetrics.EndBlockGasConsumed = testGauge{&gasConsumed}
+	testMetrics.PendingRequests = testGauge{&pending}
+	SetMetrics(testMetrics)
+	defer SetMetrics(NopMetrics())
+
+	ctx, k := setupResolveTest(t)
+	var logs bytes.Buffer
+	ctx = ctx.WithLogger(log.NewTMLogger(log.NewSyncWriter(&logs)))
+	for _, executeGas := range []uint64{10000, 1000} {
+		id := submitTestRequest(t, ctx, k, executeGas)
+		if err := k.AddPendingRequest(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if durations["entry=prepare"] != 2 {
+		t.Errorf("%d prepare durations observed, expected 2", durations["entry=prepare"])
+	}
+
+	handleEndBlock(ctx, k)
+
+	if resolved["status=success"] != 1 || resolved["status=failure"] != 1 {
+		t.Errorf("resolved requests %v, expected one success and one failure", resolved)
+	}
+	if durations["entry=execute"] != 2 {
+		t.Errorf("%d execute durations observed, expected 2", durations["entry=execute"])
+	}
+	if gasConsumed <= 1000 || gasConsumed > 11000 {
+		t.Errorf("EndBlock gas consumed %v, expected the gas used by both requests", gasConsumed)
+	}
+	if pending != 0 {
+		t.Errorf("pending requests %v, expected 0", pending)
+	}
+	if !strings.Contains(logs.String(), "request failed") || !strings.Contains(logs.String(), "reason=out_of_gas") {
+		t.Errorf("logs do not report the failed request:\n%s", logs.String())
+	}
+}
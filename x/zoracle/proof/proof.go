@@ -0,0 +1,69 @@
+// Package proof verifies oracle results against the app hash of the chain, so consumers can trust
+// a result without trusting the node that served it.
+package proof
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	"github.com/tendermint/tendermint/crypto/merkle"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+// ResultWithProof is the result of a request together with the IAVL proof of its store entry at
+// the given height. The proof is nil unless it was asked for.
+type ResultWithProof struct {
+	RequestID      types.RequestID      `json:"requestID"`
+	OracleScriptID types.OracleScriptID `json:"oracleScriptID"`
+	Calldata       []byte               `json:"calldata"`
+	Result         types.Result         `json:"result"`
+	Height         int64                `json:"height"`
+	Proof          *merkle.Proof        `json:"proof,omitempty"`
+}
+
+// ResultKeyPath returns the Merkle key path of the result store entry of the given request.
+func ResultKeyPath(requestID types.RequestID, oracleScriptID types.OracleScriptID, calldata []byte) string {
+	keyPath := merkle.KeyPath{}
+	keyPath = keyPath.AppendKey([]byte(types.StoreKey), merkle.KeyEncodingURL)
+	keyPath = keyPath.AppendKey(types.ResultStoreKey(requestID, oracleScriptID, calldata), merkle.KeyEncodingURL)
+	return keyPath.String()
+}
+
+// VerifyResult checks that the proof shows the encoded result stored for the given request under
+// the given app hash. The app hash of height H is found in the header of height H+1.
+func VerifyResult(
+	appHash []byte, proof *merkle.Proof,
+	requestID types.RequestID, oracleScriptID types.OracleScriptID, calldata []byte, result types.Result,
+) error {
+	if proof == nil {
+		return fmt.Errorf("VerifyResult: missing proof")
+	}
+	return rootmulti.DefaultProofRuntime().VerifyValue(
+		proof, appHash, ResultKeyPath(requestID, oracleScriptID, calldata), result.EncodeResult(),
+	)
+}
+
+// Verify checks the result against the app hash committed in the given signed header, which must
+// be the header of the block after the proof height and must be signed by more than two thirds of
+// the given validator set.
+func (r ResultWithProof) Verify(chainID string, header tmtypes.SignedHeader, validators *tmtypes.ValidatorSet) error {
+	if header.Header == nil || header.Commit == nil {
+		return fmt.Errorf("Verify: incomplete signed header")
+	}
+	if header.Height != r.Height+1 {
+		return fmt.Errorf("Verify: expect header at height %d but got %d", r.Height+1, header.Height)
+	}
+	if !bytes.Equal(header.ValidatorsHash, validators.Hash()) {
+		return fmt.Errorf("Verify: validator set does not match the header")
+	}
+	if err := header.ValidateBasic(chainID); err != nil {
+		return err
+	}
+	if err := validators.VerifyCommit(chainID, header.Commit.BlockID, header.Height, header.Commit); err != nil {
+		return err
+	}
+	return VerifyResult(header.AppHash, r.Proof, r.RequestID, r.OracleScriptID, r.Calldata, r.Result)
+}
@@ -0,0 +1,159 @@
+package proof
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	tmtypes "github.com/tendermint/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+const testChainID = "test-chain"
+
+// newTestResultWithProof commits a result to a zoracle store in a fresh multistore, reads it back
+// with its proof, and returns it together with the app hash of the commit.
+func newTestResultWithProof(t *testing.T) (ResultWithProof, []byte) {
+	db := dbm.NewMemDB()
+	ms := rootmulti.NewStore(db)
+	ms.SetPruning(storetypes.PruneNothing)
+	key := sdk.NewKVStoreKey(types.StoreKey)
+	otherKey := sdk.NewKVStoreKey("other")
+	ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, nil)
+	ms.MountStoreWithDB(otherKey, sdk.StoreTypeIAVL, nil)
+	if err := ms.LoadLatestVersion(); err != nil {
+		t.Fatal(err)
+	}
+
+	result := types.NewResult(1588888888, 1588888890, 4, 3, 3, []byte("result"))
+	ms.GetKVStore(key).Set(types.ResultStoreKey(1, 2, []byte("calldata")), result.EncodeResult())
+	ms.GetKVStore(key).Set(types.ResultStoreKey(2, 2, []byte("calldata")), []byte("other result"))
+	ms.GetKVStore(otherKey).Set([]byte("key"), []byte("value"))
+	commitID := ms.Commit()
+
+	resp := ms.Query(abci.RequestQuery{
+		Path:   "/" + types.StoreKey + "/key",
+		Data:   types.ResultStoreKey(1, 2, []byte("calldata")),
+		Height: commitID.Version,
+		Prove:  true,
+	})
+	if resp.Code != 0 {
+		t.Fatalf("query failed: %s", resp.Log)
+	}
+	return ResultWithProof{
+		RequestID:      1,
+		OracleScriptID: 2,
+		Calldata:       []byte("calldata"),
+		Result:         types.MustDecodeResult(resp.Value),
+		Height:         resp.Height,
+		Proof:          resp.Proof,
+	}, commitID.Hash
+}
+
+// newTestSignedHeader returns a header at the given height with the given app hash, committed by
+// every validator of a new validator set.
+func newTestSignedHeader(
+	t *testing.T, height int64, appHash []byte,
+) (tmtypes.SignedHeader, *tmtypes.ValidatorSet) {
+	validators, privValidators := tmtypes.RandValidatorSet(4, 10)
+	header := &tmtypes.Header{
+		ChainID:            testChainID,
+		Height:             height,
+		Time:               time.Unix(1588888890, 0),
+		ValidatorsHash:     validators.Hash(),
+		NextValidatorsHash: validators.Hash(),
+		AppHash:            appHash,
+		ProposerAddress:    validators.Validators[0].Address,
+	}
+	blockID := tmtypes.BlockID{
+		Hash:        header.Hash(),
+		PartsHeader: tmtypes.PartSetHeader{Total: 1, Hash: tmhash.Sum([]byte("parts"))},
+	}
+	voteSet := tmtypes.NewVoteSet(testChainID, height, 0, tmtypes.PrecommitType, validators)
+	commit, err := tmtypes.MakeCommit(blockID, height, 0, voteSet, privValidators, header.Time)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tmtypes.SignedHeader{Header: header, Commit: commit}, validators
+}
+
+func TestVerifyResultAcceptsStoreProof(t *testing.T) {
+	r, appHash := newTestResultWithProof(t)
+	if err := VerifyResult(appHash, r.Proof, r.RequestID, r.OracleScriptID, r.Calldata, r.Result); err != nil {
+		t.Fatalf("expected the proof to verify: %v", err)
+	}
+}
+
+func TestVerifyResultRejectsMismatches(t *testing.T) {
+	r, appHash := newTestResultWithProof(t)
+
+	tampered := r.Result
+	tampered.Data = []byte("forged")
+	wrongAppHash := append([]byte{}, appHash...)
+	wrongAppHash[0] ^= 0xff
+
+	cases := map[string]error{
+		"missing proof":   VerifyResult(appHash, nil, r.RequestID, r.OracleScriptID, r.Calldata, r.Result),
+		"tampered result": VerifyResult(appHash, r.Proof, r.RequestID, r.OracleScriptID, r.Calldata, tampered),
+		"wrong request":   VerifyResult(appHash, r.Proof, 2, r.OracleScriptID, r.Calldata, r.Result),
+		"wrong calldata":  VerifyResult(appHash, r.Proof, r.RequestID, r.OracleScriptID, []byte("other"), r.Result),
+		"wrong app hash":  VerifyResult(wrongAppHash, r.Proof, r.RequestID, r.OracleScriptID, r.Calldata, r.Result),
+	}
+	for name, err := range cases {
+		if err == nil {
+			t.Errorf("%s: expected verification to fail", name)
+		}
+	}
+}
+
+func TestResultWithProofVerify(t *testing.T) {
+	r, appHash := newTestResultWithProof(t)
+	header, validators := newTestSignedHeader(t, r.Height+1, appHash)
+	if err := r.Verify(testChainID, header, validators); err != nil {
+		t.Fatalf("expected the result to verify: %v", err)
+	}
+}
+
+func TestResultWithProofVerifyRejectsMismatches(t *testing.T) {
+	r, appHash := newTestResultWithProof(t)
+	header, validators := newTestSignedHeader(t, r.Height+1, appHash)
+
+	tampered := r
+	tampered.Result.Data = []byte("forged")
+	if tampered.Verify(testChainID, header, validators) == nil {
+		t.Error("tampered result: expected verification to fail")
+	}
+
+	wrongHeight, wrongHeightValidators := newTestSignedHeader(t, r.Height, appHash)
+	if r.Verify(testChainID, wrongHeight, wrongHeightValidators) == nil {
+		t.Error("header at the proof height: expected verification to fail")
+	}
+
+	wrongAppHash := append([]byte{}, appHash...)
+	wrongAppHash[0] ^= 0xff
+	otherHeader, otherValidators := newTestSignedHeader(t, r.Height+1, wrongAppHash)
+	if r.Verify(testChainID, otherHeader, otherValidators) == nil {
+		t.Error("wrong app hash: expected verification to fail")
+	}
+
+	// The header is signed by its own validators, but they are not the given validator set.
+	if r.Verify(testChainID, header, otherValidators) == nil {
+		t.Error("mismatched validator set: expected verification to fail")
+	}
+
+	if r.Verify("other-chain", header, validators) == nil {
+		t.Error("wrong chain ID: expected verification to fail")
+	}
+
+	unsigned := header
+	unsigned.Commit = nil
+	if r.Verify(testChainID, unsigned, validators) == nil {
+		t.Error("missing commit: expected verification to fail")
+	}
+}
@@ -0,0 +1,96 @@
+package zoracle
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gaia/x/zoracle/internal/keeper"
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+// setupReferenceDataTest returns a context with a bonded validator and a BTC/USD reference symbol
+// refreshed every 10 blocks. Its oracle script requests data sources 1 and 2, which charge a fee
+// of 10 each.
+func setupReferenceDataTest(t *testing.T) (sdk.Context, Keeper, sdk.Coins) {
+	ctx, k := keeper.CreateTestInput()
+	keeper.CreateTestValidators(ctx, k, 1)
+	setOracleScriptFixture(t, ctx, k, "silly.wasm")
+	provider := sdk.AccAddress([]byte("provider"))
+	fee := sdk.NewCoins(sdk.NewInt64Coin(k.StakingKeeper.BondDenom(ctx), 10))
+	for id := DataSourceID(1); id <= 2; id++ {
+		k.SetDataSource(ctx, id, types.NewDataSource(provider, "source", "description", fee, []byte("executable")))
+	}
+	k.SetReferenceSymbols(ctx, types.ReferenceSymbols{
+		types.NewReferenceSymbol("BTC/USD", 1, []byte("BTC"), 1, 1, 100, 10000, 10000, 10, 100),
+	})
+	return ctx, k, fee.Add(fee...)
+}
+
+func TestRequestReferenceSymbols(t *testing.T) {
+	ctx, k, fees := setupReferenceDataTest(t)
+	account := k.GetReferenceDataAccount()
+	keeper.FundTestAccount(ctx, k, account, fees)
+
+	handleBeginBlock(ctx, k)
+	if count := k.GetRequestCount(ctx); count != 1 {
+		t.Fatalf("request count %d, expected 1", count)
+	}
+	request := mustGetRequest(t, ctx, k, 1)
+	if !request.Requester.Equals(account) || string(request.Calldata) != "BTC" {
+		t.Errorf("request by %s with calldata %q, expected the reference data account and BTC", request.Requester, request.Calldata)
+	}
+	if balance := k.CoinKeeper.GetAllBalances(ctx, account); !balance.IsZero() {
+		t.Errorf("reference data account balance %s, expected the data source fees to be paid", balance)
+	}
+
+	// Nothing is requested again until the refresh interval has passed.
+	handleBeginBlock(ctx.WithBlockHeight(10), k)
+	if count := k.GetRequestCount(ctx); count != 1 {
+		t.Errorf("request count %d before the refresh interval, expected 1", count)
+	}
+}
+
+func TestRequestReferenceSymbolsCannotPay(t *testing.T) {
+	ctx, k, fees := setupReferenceDataTest(t)
+
+	handleBeginBlock(ctx, k)
+	if count := k.GetRequestCount(ctx); count != 0 {
+		t.Errorf("request count %d, expected no request without the data source fees", count)
+	}
+	if raw := k.GetRawDataRequests(ctx, 1); len(raw) != 0 {
+		t.Errorf("raw data requests %v, expected the failed request to be discarded", raw)
+	}
+	// The symbol is skipped until its next interval, even once the account is funded.
+	keeper.FundTestAccount(ctx, k, k.GetReferenceDataAccount(), fees)
+	handleBeginBlock(ctx.WithBlockHeight(10), k)
+	if count := k.GetRequestCount(ctx); count != 0 {
+		t.Errorf("request count %d before the refresh interval, expected 0", count)
+	}
+	handleBeginBlock(ctx.WithBlockHeight(11), k)
+	if count := k.GetRequestCount(ctx); count != 1 {
+		t.Errorf("request count %d at the refresh interval, expected 1", count)
+	}
+}
+
+func TestRequestReferenceSymbolsWithoutScript(t *testing.T) {
+	ctx, k, fees := setupReferenceDataTest(t)
+	keeper.FundTestAccount(ctx, k, k.GetReferenceDataAccount(), fees)
+	k.SetReferenceSymbols(ctx, types.ReferenceSymbols{
+		types.NewReferenceSymbol("ETH/USD", 2, []byte("ETH"), 1, 1, 100, 10000, 10000, 10, 100),
+	})
+
+	handleBeginBlock(ctx, k)
+	if count := k.GetRequestCount(ctx); count != 0 {
+		t.Errorf("request count %d, expected no request for a missing oracle script", count)
+	}
+	if height, ok := k.GetReferenceSymbolLastRequestHeight(ctx, "ETH/USD"); !ok || height != ctx.BlockHeight() {
+		t.Errorf("last request height %d, expected the symbol to wait for its next interval", height)
+	}
+
+	// Without any reference symbol, nothing is requested.
+	k.SetReferenceSymbols(ctx, types.ReferenceSymbols{})
+	handleBeginBlock(ctx.WithBlockHeight(11), k)
+	if count := k.GetRequestCount(ctx); count != 0 {
+		t.Errorf("request count %d without reference symbols, expected 0", count)
+	}
+}
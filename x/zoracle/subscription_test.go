@@ -0,0 +1,114 @@
+package zoracle
+
+import (
+	"io/ioutil"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gaia/x/zoracle/internal/keeper"
+	"github.com/cosmos/gaia/x/zoracle/internal/types"
+)
+
+// setupSubscriptionTest returns a context at height 2 with a bonded validator and a subscription
+// requested every 5 blocks until height 20, funded with the given amount of the bond denomination.
+func setupSubscriptionTest(t *testing.T, deposit int64) (sdk.Context, Keeper, SubscriptionID, sdk.AccAddress) {
+	ctx, k := keeper.CreateTestInput()
+	keeper.CreateTestValidators(ctx, k, 1)
+	code, err := ioutil.ReadFile("../../owasm/res/main.wasm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := sdk.AccAddress([]byte("owner_______________"))
+	k.SetOracleScript(ctx, 1, types.NewOracleScript(owner, "script", "description", code))
+
+	coins := sdk.NewCoins(sdk.NewInt64Coin(k.StakingKeeper.BondDenom(ctx), deposit))
+	keeper.FundTestAccount(ctx, k, owner, coins)
+	id, err := k.AddSubscription(ctx, owner, 1, []byte("calldata"), 1, 1, 10, 1000, 1000, 5, 20, coins)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ctx.WithBlockHeight(2), k, id, owner
+}
+
+func TestRequestSubscriptionsChargesGasAndReschedules(t *testing.T) {
+	ctx, k, id, _ := setupSubscriptionTest(t, 1000000)
+
+	requestSubscriptions(ctx, k)
+
+	if count := k.GetRequestCount(ctx); count != 1 {
+		t.Fatalf("request count %d, expected 1", count)
+	}
+	subscription, err := k.GetSubscription(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subscription.NextHeight != 7 {
+		t.Errorf("next height %d, expected the subscription to be rescheduled at 7", subscription.NextHeight)
+	}
+	// The request uses at least its prepare and execute gas.
+	spent := 1000000 - subscription.Balance.AmountOf(k.StakingKeeper.BondDenom(ctx)).Int64()
+	if spent < 2000 {
+		t.Errorf("charged %d, expected at least the prepare and execute gas", spent)
+	}
+
+	// Nothing is requested again until the next interval.
+	requestSubscriptions(ctx.WithBlockHeight(6), k)
+	if count := k.GetRequestCount(ctx); count != 1 {
+		t.Errorf("request count %d before the next interval, expected 1", count)
+	}
+	requestSubscriptions(ctx.WithBlockHeight(7), k)
+	if count := k.GetRequestCount(ctx); count != 2 {
+		t.Errorf("request count %d at the next interval, expected 2", count)
+	}
+}
+
+func TestRequestSubscriptionsClosesOnInsufficientBalance(t *testing.T) {
+	ctx, k, id, owner := setupSubscriptionTest(t, 100)
+
+	requestSubscriptions(ctx, k)
+
+	if count := k.GetRequestCount(ctx); count != 0 {
+		t.Errorf("request count %d, expected no request without the balance for its gas", count)
+	}
+	if _, err := k.GetSubscription(ctx, id); err == nil {
+		t.Error("expected the subscription to be closed")
+	}
+	bondDenom := k.StakingKeeper.BondDenom(ctx)
+	if balance := k.CoinKeeper.GetBalance(ctx, owner, bondDenom); !balance.Equal(sdk.NewInt64Coin(bondDenom, 100)) {
+		t.Errorf("owner balance %s, expected the deposit back", balance)
+	}
+	closed := false
+	for _, event := range ctx.EventManager().Events() {
+		if event.Type == types.EventTypeSubscriptionClosed {
+			closed = true
+		}
+	}
+	if !closed {
+		t.Error("expected a subscription closed event")
+	}
+}
+
+func TestRequestSubscriptionsPerBlockLimit(t *testing.T) {
+	ctx, k, first, _ := setupSubscriptionTest(t, 1000000)
+	owner := sdk.AccAddress([]byte("owner_______________"))
+	coins := sdk.NewCoins(sdk.NewInt64Coin(k.StakingKeeper.BondDenom(ctx), 1000000))
+	keeper.FundTestAccount(ctx, k, owner, coins)
+	second, err := k.AddSubscription(ctx.WithBlockHeight(1), owner, 1, []byte("calldata"), 1, 1, 10, 1000, 1000, 5, 20, coins)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k.SetMaxSubscriptionsPerBlock(ctx, 1)
+
+	requestSubscriptions(ctx, k)
+	if subscription, _ := k.GetSubscription(ctx, first); subscription.NextHeight != 7 {
+		t.Errorf("first subscription: next height %d, expected 7", subscription.NextHeight)
+	}
+	if subscription, _ := k.GetSubscription(ctx, second); subscription.NextHeight != 2 {
+		t.Errorf("second subscription: next height %d, expected it to wait for the next block", subscription.NextHeight)
+	}
+
+	requestSubscriptions(ctx.WithBlockHeight(3), k)
+	if subscription, _ := k.GetSubscription(ctx, second); subscription.NextHeight != 7 {
+		t.Errorf("second subscription: next height %d, expected 7", subscription.NextHeight)
+	}
+}